@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/moba1/usd/bidi"
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+	"golang.org/x/text/unicode/runenames"
+)
+
+const bidiCmdName = "bidi"
+
+func parseDirection(directionHolder *bidi.Direction, s string) error {
+	switch s {
+	case "Auto":
+		*directionHolder = bidi.Auto
+	case "LTR":
+		*directionHolder = bidi.LeftToRight
+	case "RTL":
+		*directionHolder = bidi.RightToLeft
+	default:
+		return fmt.Errorf("unknown direction: %s", s)
+	}
+	return nil
+}
+
+func parseBidiCmd(args []string) func() error {
+	bidiCmd := flag.NewFlagSet(bidiCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(bidiCmd)
+	direction := bidi.Auto
+	bidiCmd.Func("direction", "paragraph `direction`. default is 'Auto' (value: Auto|LTR|RTL)", func(s string) error {
+		return parseDirection(&direction, s)
+	})
+	var characters bool
+	bidiCmd.BoolVar(&characters, "characters", false, "show the level and Bidi_Class of each character")
+	bidiCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", bidiCmdName),
+			fmt.Sprintf("  %s [option]", bidiCmdName),
+			"Each line of the input is reordered as a paragraph with the Unicode Bidirectional Algorithm.",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(bidiCmd.Output(), stmt)
+		}
+		bidiCmd.PrintDefaults()
+	}
+	if err := bidiCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		table := newTableEncoder(tableOutput)
+		if !noHeader {
+			if characters {
+				table.SetHeader([]string{"Line", "Column", "Character", "Code Point", "Name", "Bidi Class", "Level", "Visual Column"})
+			} else {
+				table.SetHeader([]string{"Line", "Paragraph Level", "Logical", "Visual"})
+			}
+		}
+		lineNumber := 0
+		err = scanLines(os.Stdin, cs, func(line []unicode.Char) error {
+			lineNumber++
+			runes := make([]rune, len(line))
+			for i, c := range line {
+				if c.Err != nil {
+					return fmt.Errorf("line %d, column %d (reason; %s)", c.Line, c.Column, c.Err.Error())
+				}
+				runes[i] = c.Rune
+			}
+			p := bidi.Resolve(runes, direction)
+			order := p.VisualOrder()
+
+			if characters {
+				visualColumns := make([]int, len(order))
+				for column, i := range order {
+					visualColumns[i] = column + 1
+				}
+				for i, c := range p.Runes {
+					level := "x"
+					if p.Levels[i] >= 0 {
+						level = strconv.Itoa(p.Levels[i])
+					}
+					table.Append([]string{
+						strconv.Itoa(lineNumber),
+						strconv.Itoa(i + 1),
+						encoder.GraphicString(c),
+						fmt.Sprintf("%U", c),
+						runenames.Name(c),
+						bidi.ClassName(p.Classes[i]),
+						level,
+						strconv.Itoa(visualColumns[i]),
+					})
+				}
+				return nil
+			}
+
+			var logical, visual strings.Builder
+			for _, c := range p.Runes {
+				logical.WriteString(encoder.GraphicString(c))
+			}
+			for _, c := range p.VisualString() {
+				visual.WriteString(encoder.GraphicString(c))
+			}
+			table.Append([]string{
+				strconv.Itoa(lineNumber),
+				strconv.Itoa(p.Level),
+				logical.String(),
+				visual.String(),
+			})
+			return nil
+		})
+		if renderErr := table.Render(); err == nil {
+			err = renderErr
+		}
+		return err
+	}
+}
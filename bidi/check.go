@@ -0,0 +1,172 @@
+// Package bidi finds bidirectional controls and invisible characters which can
+// make source code read differently from how it is compiled (CVE-2021-42574),
+// and previews the visual order of text with the Unicode Bidirectional
+// Algorithm.
+package bidi
+
+import (
+	"sort"
+	stdunicode "unicode"
+
+	"github.com/moba1/usd/unicode"
+)
+
+// Kind is a kind of finding.
+type Kind int
+
+const (
+	// Control is an explicit directional embedding, override or isolate
+	// control.
+	Control Kind = iota
+	// Unterminated is an embedding, override or isolate which isn't closed
+	// before the end of the line.
+	Unterminated
+	// Unmatched is a PDF or PDI without a corresponding opening control.
+	Unmatched
+	// ZeroWidth is a zero width character inside an identifier or a string
+	// literal.
+	ZeroWidth
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Control:
+		return "Control"
+	case Unterminated:
+		return "Unterminated"
+	case Unmatched:
+		return "Unmatched"
+	case ZeroWidth:
+		return "ZeroWidth"
+	}
+	return "Unknown"
+}
+
+// Finding is a suspicious character found by Check.
+type Finding struct {
+	Kind Kind
+	Char unicode.Char
+}
+
+var controlNames = map[rune]string{
+	'\u202A': "LRE",
+	'\u202B': "RLE",
+	'\u202C': "PDF",
+	'\u202D': "LRO",
+	'\u202E': "RLO",
+	'\u2066': "LRI",
+	'\u2067': "RLI",
+	'\u2068': "FSI",
+	'\u2069': "PDI",
+}
+
+// ControlName returns the abbreviation of an explicit directional control
+// such as "RLO".
+func ControlName(c rune) (string, bool) {
+	name, ok := controlNames[c]
+	return name, ok
+}
+
+// IsZeroWidth reports whether c is a zero width character which is invisible
+// between other characters.
+func IsZeroWidth(c rune) bool {
+	switch c {
+	case '\u180E', '\u200B', '\u200C', '\u200D', '\u2060', '\uFEFF':
+		return true
+	}
+	return false
+}
+
+func isIdentifierChar(c rune) bool {
+	return c == '_' || c == '$' || stdunicode.In(c, stdunicode.L, stdunicode.M, stdunicode.Nd, stdunicode.Nl, stdunicode.Pc)
+}
+
+// Check returns the findings of a line in the order of their positions. An
+// unterminated control is reported by both a Control and an Unterminated
+// finding.
+func Check(line []unicode.Char) []Finding {
+	var findings []Finding
+	// stack holds the open embeddings and isolates in order. PDF closes the
+	// last embedding unless an isolate was opened after it, and PDI closes
+	// the last isolate and the embeddings opened inside it.
+	var stack []unicode.Char
+	var quote rune
+	escaped := false
+	for i, c := range line {
+		if c.Err != nil {
+			continue
+		}
+		if name, ok := controlNames[c.Rune]; ok {
+			findings = append(findings, Finding{Kind: Control, Char: c})
+			switch name {
+			case "LRE", "RLE", "LRO", "RLO", "LRI", "RLI", "FSI":
+				stack = append(stack, c)
+			case "PDF":
+				if n := len(stack); n > 0 && !isIsolate(stack[n-1].Rune) {
+					stack = stack[:n-1]
+				} else {
+					findings = append(findings, Finding{Kind: Unmatched, Char: c})
+				}
+			case "PDI":
+				n := len(stack)
+				for n > 0 && !isIsolate(stack[n-1].Rune) {
+					n--
+				}
+				if n > 0 {
+					stack = stack[:n-1]
+				} else {
+					findings = append(findings, Finding{Kind: Unmatched, Char: c})
+				}
+			}
+			continue
+		}
+
+		if IsZeroWidth(c.Rune) {
+			// Characters are compared with the nearest visible neighbours so
+			// that a run of zero width characters is reported as a whole.
+			if quote != 0 || (isIdentifierChar(neighbour(line, i, -1)) && isIdentifierChar(neighbour(line, i, 1))) {
+				findings = append(findings, Finding{Kind: ZeroWidth, Char: c})
+			}
+			continue
+		}
+
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && c.Rune == '\\':
+			escaped = true
+		case quote != 0 && c.Rune == quote:
+			quote = 0
+		case quote == 0 && (c.Rune == '"' || c.Rune == '\'' || c.Rune == '`'):
+			quote = c.Rune
+		}
+	}
+	for _, c := range stack {
+		findings = append(findings, Finding{Kind: Unterminated, Char: c})
+	}
+	sortFindings(findings)
+	return findings
+}
+
+func isIsolate(c rune) bool {
+	return c == '\u2066' || c == '\u2067' || c == '\u2068'
+}
+
+// neighbour returns the nearest character to line[i] in direction dir which
+// isn't zero width, or 0 at the ends of the line.
+func neighbour(line []unicode.Char, i, dir int) rune {
+	for j := i + dir; j >= 0 && j < len(line); j += dir {
+		if line[j].Err == nil && !IsZeroWidth(line[j].Rune) {
+			return line[j].Rune
+		}
+	}
+	return 0
+}
+
+// sortFindings orders findings by position, keeping the order of findings at
+// the same position.
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Char.Offset < findings[j].Char.Offset
+	})
+}
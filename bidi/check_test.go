@@ -0,0 +1,95 @@
+package bidi_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/moba1/usd/bidi"
+	"github.com/moba1/usd/unicode"
+)
+
+func scanLine(t *testing.T, s string) []unicode.Char {
+	scanner := unicode.NewScanner(bytes.NewBufferString(s), unicode.ReadUtf8Char)
+	var line []unicode.Char
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			return line
+		} else if err != nil {
+			t.Fatalf("Scanner.Scan returns error: %v", err)
+		}
+		line = append(line, c)
+	}
+}
+
+type finding struct {
+	kind   bidi.Kind
+	char   rune
+	column int
+}
+
+func check(t *testing.T, s string, expected []finding) {
+	findings := bidi.Check(scanLine(t, s))
+	if len(findings) != len(expected) {
+		t.Fatalf("bidi.Check(%q) returns %+v, but expected value is %+v", s, findings, expected)
+	}
+	for i, f := range findings {
+		e := expected[i]
+		if f.Kind != e.kind || f.Char.Rune != e.char || f.Char.Column != e.column {
+			t.Errorf("bidi.Check(%q)[%d] is %v %U at column %d, but expected value is %v %U at column %d", s, i, f.Kind, f.Char.Rune, f.Char.Column, e.kind, e.char, e.column)
+		}
+	}
+}
+
+func TestCheck_Balanced(t *testing.T) {
+	check(t, "a\u202Eb\u202Cc\u2067d\u2069", []finding{
+		{bidi.Control, '\u202E', 2},
+		{bidi.Control, '\u202C', 4},
+		{bidi.Control, '\u2067', 6},
+		{bidi.Control, '\u2069', 8},
+	})
+	// PDI closes the embeddings inside the isolate.
+	check(t, "\u2066\u202B\u2069", []finding{
+		{bidi.Control, '\u2066', 1},
+		{bidi.Control, '\u202B', 2},
+		{bidi.Control, '\u2069', 3},
+	})
+}
+
+func TestCheck_Unbalanced(t *testing.T) {
+	// The Trojan Source "commenting-out" pattern.
+	check(t, "/*\u202E } \u2066if (isAdmin)\u2069 \u2066 begin admins only */", []finding{
+		{bidi.Control, '\u202E', 3},
+		{bidi.Unterminated, '\u202E', 3},
+		{bidi.Control, '\u2066', 7},
+		{bidi.Control, '\u2069', 20},
+		{bidi.Control, '\u2066', 22},
+		{bidi.Unterminated, '\u2066', 22},
+	})
+	// PDF can't close an embedding outside of the isolate.
+	check(t, "\u202A\u2067\u202C\u2069\u202C", []finding{
+		{bidi.Control, '\u202A', 1},
+		{bidi.Control, '\u2067', 2},
+		{bidi.Control, '\u202C', 3},
+		{bidi.Unmatched, '\u202C', 3},
+		{bidi.Control, '\u2069', 4},
+		{bidi.Control, '\u202C', 5},
+	})
+	check(t, "\u2069", []finding{
+		{bidi.Control, '\u2069', 1},
+		{bidi.Unmatched, '\u2069', 1},
+	})
+}
+
+func TestCheck_ZeroWidth(t *testing.T) {
+	check(t, "if access\u200B_level != \"user\u200C\" {", []finding{
+		{bidi.ZeroWidth, '\u200B', 10},
+		{bidi.ZeroWidth, '\u200C', 26},
+	})
+	// Zero width characters between words and after escaped quotes.
+	check(t, "a \u200B b \"\\\"\u2060\"", []finding{
+		{bidi.ZeroWidth, '\u2060', 10},
+	})
+	check(t, "\uFEFFpackage main", nil)
+}
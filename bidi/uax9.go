@@ -0,0 +1,722 @@
+package bidi
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/moba1/usd/ucd"
+	xbidi "golang.org/x/text/unicode/bidi"
+)
+
+//go:embed data/BidiBrackets.txt
+var bidiBracketsTxt string
+
+//go:embed data/BidiMirroring.txt
+var bidiMirroringTxt string
+
+// Class is a Bidi_Class value.
+type Class = xbidi.Class
+
+var classNames = map[Class]string{
+	xbidi.L:   "L",
+	xbidi.R:   "R",
+	xbidi.EN:  "EN",
+	xbidi.ES:  "ES",
+	xbidi.ET:  "ET",
+	xbidi.AN:  "AN",
+	xbidi.CS:  "CS",
+	xbidi.B:   "B",
+	xbidi.S:   "S",
+	xbidi.WS:  "WS",
+	xbidi.ON:  "ON",
+	xbidi.BN:  "BN",
+	xbidi.NSM: "NSM",
+	xbidi.AL:  "AL",
+	xbidi.LRO: "LRO",
+	xbidi.RLO: "RLO",
+	xbidi.LRE: "LRE",
+	xbidi.RLE: "RLE",
+	xbidi.PDF: "PDF",
+	xbidi.LRI: "LRI",
+	xbidi.RLI: "RLI",
+	xbidi.FSI: "FSI",
+	xbidi.PDI: "PDI",
+}
+
+// ClassOf returns the Bidi_Class of c.
+func ClassOf(c rune) Class {
+	props, _ := xbidi.LookupRune(c)
+	return props.Class()
+}
+
+// ClassName returns the short alias of a Bidi_Class such as "AL".
+func ClassName(c Class) string {
+	if name, ok := classNames[c]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// Direction is a paragraph direction.
+type Direction int
+
+const (
+	// Auto takes the direction from the first strong character (rules P2 and
+	// P3).
+	Auto Direction = iota
+	LeftToRight
+	RightToLeft
+)
+
+type bracket struct {
+	pair rune
+	open bool
+}
+
+var (
+	loadOnce sync.Once
+	brackets map[rune]bracket
+	mirrors  map[rune]rune
+)
+
+func load() {
+	loadOnce.Do(func() {
+		brackets = map[rune]bracket{}
+		ucd.ParseFields(bidiBracketsTxt, func(fields []string) {
+			c, _ := ucd.ParseDataRange(fields[0])
+			pair, _ := ucd.ParseDataRange(fields[1])
+			brackets[c] = bracket{pair: pair, open: fields[2] == "o"}
+		})
+		mirrors = map[rune]rune{}
+		ucd.ParseFields(bidiMirroringTxt, func(fields []string) {
+			c, _ := ucd.ParseDataRange(fields[0])
+			mirrors[c], _ = ucd.ParseDataRange(fields[1])
+		})
+	})
+}
+
+// Mirror returns the Bidi_Mirroring_Glyph of c, the character which is
+// displayed instead of c at a right-to-left level.
+func Mirror(c rune) (rune, bool) {
+	load()
+	m, ok := mirrors[c]
+	return m, ok
+}
+
+// Paragraph is a paragraph resolved by the Unicode Bidirectional Algorithm
+// (UAX #9).
+type Paragraph struct {
+	Runes []rune
+	// Classes are the Bidi_Class values of Runes before resolution.
+	Classes []Class
+	// Levels are the embedding levels of Runes after rule L1. Characters
+	// removed by rule X9 have level -1.
+	Levels []int
+	// Level is the paragraph embedding level.
+	Level int
+}
+
+const maxDepth = 125
+
+func isIsolateInitiator(c Class) bool {
+	return c == xbidi.LRI || c == xbidi.RLI || c == xbidi.FSI
+}
+
+func isRemovedByX9(c Class) bool {
+	switch c {
+	case xbidi.LRE, xbidi.RLE, xbidi.LRO, xbidi.RLO, xbidi.PDF, xbidi.BN:
+		return true
+	}
+	return false
+}
+
+func isNeutralOrIsolate(c Class) bool {
+	switch c {
+	case xbidi.B, xbidi.S, xbidi.WS, xbidi.ON, xbidi.LRI, xbidi.RLI, xbidi.FSI, xbidi.PDI:
+		return true
+	}
+	return false
+}
+
+// directionOf returns L or R for a level.
+func directionOf(level int) Class {
+	if level%2 == 0 {
+		return xbidi.L
+	}
+	return xbidi.R
+}
+
+// strongDirection returns the direction which c counts as in rules N0 and
+// N1, or ON when c is neutral.
+func strongDirection(c Class) Class {
+	switch c {
+	case xbidi.L:
+		return xbidi.L
+	case xbidi.R, xbidi.AL, xbidi.EN, xbidi.AN:
+		return xbidi.R
+	}
+	return xbidi.ON
+}
+
+type resolver struct {
+	classes []Class
+	types   []Class
+	levels  []int
+	// matchingPDI is the index of the PDI matching each isolate initiator, or
+	// len(classes) when it has none (BD9).
+	matchingPDI map[int]int
+	// matchedPDI holds the PDIs which have an isolate initiator.
+	matchedPDI map[int]bool
+	level      int
+}
+
+// Resolve runs the Unicode Bidirectional Algorithm over a single paragraph.
+func Resolve(runes []rune, direction Direction) *Paragraph {
+	load()
+	r := &resolver{
+		classes:     make([]Class, len(runes)),
+		types:       make([]Class, len(runes)),
+		levels:      make([]int, len(runes)),
+		matchingPDI: map[int]int{},
+		matchedPDI:  map[int]bool{},
+	}
+	for i, c := range runes {
+		r.classes[i] = ClassOf(c)
+		r.types[i] = r.classes[i]
+	}
+	r.matchIsolates()
+
+	switch direction {
+	case LeftToRight:
+		r.level = 0
+	case RightToLeft:
+		r.level = 1
+	default:
+		r.level = r.firstStrongLevel(0, len(runes), 0)
+	}
+	r.explicitLevels()
+	for _, sequence := range r.isolatingRunSequences() {
+		sequence.resolveWeakTypes()
+		sequence.resolveBracketPairs(runes)
+		sequence.resolveNeutralTypes()
+		sequence.resolveImplicitLevels()
+	}
+	r.resetWhitespaceLevels()
+
+	levels := make([]int, len(runes))
+	for i := range levels {
+		if isRemovedByX9(r.classes[i]) {
+			levels[i] = -1
+		} else {
+			levels[i] = r.levels[i]
+		}
+	}
+	return &Paragraph{
+		Runes:   runes,
+		Classes: r.classes,
+		Levels:  levels,
+		Level:   r.level,
+	}
+}
+
+func (r *resolver) matchIsolates() {
+	var stack []int
+	for i, c := range r.classes {
+		switch {
+		case isIsolateInitiator(c):
+			stack = append(stack, i)
+			r.matchingPDI[i] = len(r.classes)
+		case c == xbidi.PDI && len(stack) > 0:
+			r.matchingPDI[stack[len(stack)-1]] = i
+			r.matchedPDI[i] = true
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// firstStrongLevel applies rules P2 and P3 to classes[start:end], skipping
+// isolates.
+func (r *resolver) firstStrongLevel(start, end, defaultLevel int) int {
+	for i := start; i < end; i++ {
+		switch c := r.classes[i]; {
+		case c == xbidi.L:
+			return 0
+		case c == xbidi.R || c == xbidi.AL:
+			return 1
+		case isIsolateInitiator(c):
+			i = r.matchingPDI[i]
+		}
+	}
+	return defaultLevel
+}
+
+type status struct {
+	level    int
+	override Class
+	isolate  bool
+}
+
+// explicitLevels applies rules X1 to X8.
+func (r *resolver) explicitLevels() {
+	stack := []status{{level: r.level, override: xbidi.ON}}
+	overflowIsolates, overflowEmbeddings, validIsolates := 0, 0, 0
+	nextLevel := func(rtl bool) int {
+		level := stack[len(stack)-1].level
+		if rtl {
+			return (level + 1) | 1
+		}
+		return (level + 2) &^ 1
+	}
+	for i, c := range r.classes {
+		last := stack[len(stack)-1]
+		switch c {
+		case xbidi.RLE, xbidi.LRE, xbidi.RLO, xbidi.LRO:
+			r.levels[i] = last.level
+			level := nextLevel(c == xbidi.RLE || c == xbidi.RLO)
+			if level <= maxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				override := xbidi.ON
+				if c == xbidi.RLO {
+					override = xbidi.R
+				} else if c == xbidi.LRO {
+					override = xbidi.L
+				}
+				stack = append(stack, status{level: level, override: override})
+			} else if overflowIsolates == 0 {
+				overflowEmbeddings++
+			}
+		case xbidi.RLI, xbidi.LRI, xbidi.FSI:
+			r.levels[i] = last.level
+			if last.override != xbidi.ON {
+				r.types[i] = last.override
+			}
+			rtl := c == xbidi.RLI
+			if c == xbidi.FSI {
+				rtl = r.firstStrongLevel(i+1, r.matchingPDI[i], 0) == 1
+			}
+			level := nextLevel(rtl)
+			if level <= maxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				validIsolates++
+				stack = append(stack, status{level: level, override: xbidi.ON, isolate: true})
+			} else {
+				overflowIsolates++
+			}
+		case xbidi.PDI:
+			if overflowIsolates > 0 {
+				overflowIsolates--
+			} else if validIsolates > 0 {
+				overflowEmbeddings = 0
+				for !stack[len(stack)-1].isolate {
+					stack = stack[:len(stack)-1]
+				}
+				stack = stack[:len(stack)-1]
+				validIsolates--
+			}
+			last = stack[len(stack)-1]
+			r.levels[i] = last.level
+			if last.override != xbidi.ON {
+				r.types[i] = last.override
+			}
+		case xbidi.PDF:
+			r.levels[i] = last.level
+			switch {
+			case overflowIsolates > 0:
+			case overflowEmbeddings > 0:
+				overflowEmbeddings--
+			case !last.isolate && len(stack) >= 2:
+				stack = stack[:len(stack)-1]
+			}
+		case xbidi.B:
+			r.levels[i] = r.level
+		case xbidi.BN:
+			r.levels[i] = last.level
+		default:
+			r.levels[i] = last.level
+			if last.override != xbidi.ON {
+				r.types[i] = last.override
+			}
+		}
+	}
+}
+
+type sequence struct {
+	r       *resolver
+	indexes []int
+	types   []Class
+	level   int
+	sos     Class
+	eos     Class
+}
+
+// isolatingRunSequences applies rules X9 and X10.
+func (r *resolver) isolatingRunSequences() []*sequence {
+	// level runs of the characters which remain after X9
+	var runs [][]int
+	var run []int
+	for i, c := range r.classes {
+		if isRemovedByX9(c) {
+			continue
+		}
+		if len(run) > 0 && r.levels[run[0]] != r.levels[i] {
+			runs = append(runs, run)
+			run = nil
+		}
+		run = append(run, i)
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+
+	runOf := map[int]int{}
+	for i, run := range runs {
+		runOf[run[0]] = i
+	}
+	var sequences []*sequence
+	for _, run := range runs {
+		if r.classes[run[0]] == xbidi.PDI && r.matchedPDI[run[0]] {
+			// continuation of the sequence of the matching isolate initiator
+			continue
+		}
+		indexes := append([]int{}, run...)
+		for {
+			last := indexes[len(indexes)-1]
+			if !isIsolateInitiator(r.classes[last]) {
+				break
+			}
+			pdi := r.matchingPDI[last]
+			next, ok := runOf[pdi]
+			if !ok {
+				break
+			}
+			indexes = append(indexes, runs[next]...)
+		}
+		sequences = append(sequences, r.newSequence(indexes))
+	}
+	return sequences
+}
+
+func (r *resolver) newSequence(indexes []int) *sequence {
+	s := &sequence{r: r, indexes: indexes, level: r.levels[indexes[0]]}
+	s.types = make([]Class, len(indexes))
+	for i, index := range indexes {
+		s.types[i] = r.types[index]
+	}
+
+	before := r.level
+	for i := indexes[0] - 1; i >= 0; i-- {
+		if !isRemovedByX9(r.classes[i]) {
+			before = r.levels[i]
+			break
+		}
+	}
+	after := r.level
+	if last := indexes[len(indexes)-1]; !isIsolateInitiator(r.classes[last]) {
+		for i := last + 1; i < len(r.classes); i++ {
+			if !isRemovedByX9(r.classes[i]) {
+				after = r.levels[i]
+				break
+			}
+		}
+	}
+	s.sos = directionOf(maxInt(before, s.level))
+	s.eos = directionOf(maxInt(after, s.level))
+	return s
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// resolveWeakTypes applies rules W1 to W7.
+func (s *sequence) resolveWeakTypes() {
+	types := s.types
+	// W1
+	for i, t := range types {
+		if t != xbidi.NSM {
+			continue
+		}
+		if i == 0 {
+			types[i] = s.sos
+		} else if prev := types[i-1]; isIsolateInitiator(prev) || prev == xbidi.PDI {
+			types[i] = xbidi.ON
+		} else {
+			types[i] = prev
+		}
+	}
+	// W2 and W3
+	strong := s.sos
+	for i, t := range types {
+		switch t {
+		case xbidi.L, xbidi.R:
+			strong = t
+		case xbidi.AL:
+			strong = t
+			types[i] = xbidi.R
+		case xbidi.EN:
+			if strong == xbidi.AL {
+				types[i] = xbidi.AN
+			}
+		}
+	}
+	// W4
+	for i := 1; i < len(types)-1; i++ {
+		prev, next := types[i-1], types[i+1]
+		switch {
+		case types[i] == xbidi.ES && prev == xbidi.EN && next == xbidi.EN:
+			types[i] = xbidi.EN
+		case types[i] == xbidi.CS && prev == xbidi.EN && next == xbidi.EN:
+			types[i] = xbidi.EN
+		case types[i] == xbidi.CS && prev == xbidi.AN && next == xbidi.AN:
+			types[i] = xbidi.AN
+		}
+	}
+	// W5
+	for i := 0; i < len(types); i++ {
+		if types[i] != xbidi.ET {
+			continue
+		}
+		end := i
+		for end < len(types) && types[end] == xbidi.ET {
+			end++
+		}
+		if (i > 0 && types[i-1] == xbidi.EN) || (end < len(types) && types[end] == xbidi.EN) {
+			for j := i; j < end; j++ {
+				types[j] = xbidi.EN
+			}
+		}
+		i = end
+	}
+	// W6
+	for i, t := range types {
+		if t == xbidi.ES || t == xbidi.ET || t == xbidi.CS {
+			types[i] = xbidi.ON
+		}
+	}
+	// W7
+	strong = s.sos
+	for i, t := range types {
+		switch t {
+		case xbidi.L, xbidi.R:
+			strong = t
+		case xbidi.EN:
+			if strong == xbidi.L {
+				types[i] = xbidi.L
+			}
+		}
+	}
+}
+
+const maxBracketPairs = 63
+
+// canonicalBracket maps the brackets which are canonically equivalent to
+// others (BD16).
+func canonicalBracket(c rune) rune {
+	switch c {
+	case 0x2329:
+		return 0x3008
+	case 0x232A:
+		return 0x3009
+	}
+	return c
+}
+
+// resolveBracketPairs applies rule N0.
+func (s *sequence) resolveBracketPairs(runes []rune) {
+	type opening struct {
+		position int
+		pair     rune
+	}
+	var stack []opening
+	var pairs [][2]int
+	for i, index := range s.indexes {
+		if s.types[i] != xbidi.ON {
+			continue
+		}
+		b, ok := brackets[runes[index]]
+		if !ok {
+			continue
+		}
+		if b.open {
+			if len(stack) == maxBracketPairs {
+				break
+			}
+			stack = append(stack, opening{position: i, pair: canonicalBracket(b.pair)})
+			continue
+		}
+		for j := len(stack) - 1; j >= 0; j-- {
+			if stack[j].pair == canonicalBracket(runes[index]) {
+				pairs = append(pairs, [2]int{stack[j].position, i})
+				stack = stack[:j]
+				break
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0]
+	})
+
+	embedding := directionOf(s.level)
+	for _, pair := range pairs {
+		open, close := pair[0], pair[1]
+		found := xbidi.ON
+		for i := open + 1; i < close; i++ {
+			d := strongDirection(s.types[i])
+			if d == embedding {
+				found = embedding
+				break
+			} else if d != xbidi.ON {
+				found = d
+			}
+		}
+		if found == xbidi.ON {
+			continue
+		}
+		if found != embedding {
+			context := s.sos
+			for i := open - 1; i >= 0; i-- {
+				if d := strongDirection(s.types[i]); d != xbidi.ON {
+					context = d
+					break
+				}
+			}
+			if context != found {
+				found = embedding
+			}
+		}
+		for _, position := range []int{open, close} {
+			s.types[position] = found
+			for i := position + 1; i < len(s.types) && s.r.classes[s.indexes[i]] == xbidi.NSM; i++ {
+				s.types[i] = found
+			}
+		}
+	}
+}
+
+// resolveNeutralTypes applies rules N1 and N2.
+func (s *sequence) resolveNeutralTypes() {
+	types := s.types
+	for i := 0; i < len(types); i++ {
+		if !isNeutralOrIsolate(types[i]) {
+			continue
+		}
+		end := i
+		for end < len(types) && isNeutralOrIsolate(types[end]) {
+			end++
+		}
+		before := s.sos
+		if i > 0 {
+			before = strongDirection(types[i-1])
+		}
+		after := s.eos
+		if end < len(types) {
+			after = strongDirection(types[end])
+		}
+		resolved := directionOf(s.level)
+		if before == after {
+			resolved = before
+		}
+		for j := i; j < end; j++ {
+			types[j] = resolved
+		}
+		i = end
+	}
+}
+
+// resolveImplicitLevels applies rules I1 and I2.
+func (s *sequence) resolveImplicitLevels() {
+	for i, index := range s.indexes {
+		level := s.r.levels[index]
+		switch t := s.types[i]; {
+		case level%2 == 0 && t == xbidi.R:
+			level++
+		case level%2 == 0 && (t == xbidi.AN || t == xbidi.EN):
+			level += 2
+		case level%2 == 1 && (t == xbidi.L || t == xbidi.EN || t == xbidi.AN):
+			level++
+		}
+		s.r.levels[index] = level
+	}
+}
+
+// resetWhitespaceLevels applies rule L1 to the whole paragraph as a single
+// line.
+func (r *resolver) resetWhitespaceLevels() {
+	trailing := true
+	for i := len(r.classes) - 1; i >= 0; i-- {
+		switch c := r.classes[i]; {
+		case c == xbidi.S || c == xbidi.B:
+			r.levels[i] = r.level
+			trailing = true
+		case c == xbidi.WS || isIsolateInitiator(c) || c == xbidi.PDI || isRemovedByX9(c):
+			if trailing {
+				r.levels[i] = r.level
+			}
+		default:
+			trailing = false
+		}
+	}
+}
+
+// VisualOrder returns the indexes of Runes in visual order from left to right
+// (rule L2). Characters removed by rule X9 are placed with the preceding
+// character.
+func (p *Paragraph) VisualOrder() []int {
+	levels := make([]int, len(p.Levels))
+	highest, lowestOdd := 0, maxDepth+2
+	for i, level := range p.Levels {
+		if level < 0 {
+			level = p.Level
+			if i > 0 {
+				level = levels[i-1]
+			}
+		}
+		levels[i] = level
+		if level > highest {
+			highest = level
+		}
+		if level%2 == 1 && level < lowestOdd {
+			lowestOdd = level
+		}
+	}
+
+	order := make([]int, len(levels))
+	for i := range order {
+		order[i] = i
+	}
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(order); i++ {
+			if levels[order[i]] < level {
+				continue
+			}
+			end := i
+			for end < len(order) && levels[order[end]] >= level {
+				end++
+			}
+			for a, b := i, end-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = end
+		}
+	}
+	return order
+}
+
+// VisualString returns Runes in visual order with the characters at
+// right-to-left levels mirrored (rule L4).
+func (p *Paragraph) VisualString() string {
+	var b strings.Builder
+	for _, i := range p.VisualOrder() {
+		c := p.Runes[i]
+		if p.Levels[i]%2 == 1 {
+			if m, ok := Mirror(c); ok {
+				c = m
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,182 @@
+package bidi_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/moba1/usd/bidi"
+)
+
+func TestResolve(t *testing.T) {
+	testCases := []struct {
+		text      string
+		direction bidi.Direction
+		level     int
+		levels    []int
+		visual    string
+	}{
+		{"abc אבג def", bidi.Auto, 0, []int{0, 0, 0, 0, 1, 1, 1, 0, 0, 0, 0}, "abc גבא def"},
+		{"abc אבג def", bidi.RightToLeft, 1, []int{2, 2, 2, 1, 1, 1, 1, 1, 2, 2, 2}, "def גבא abc"},
+		{"אבג abc 123", bidi.Auto, 1, []int{1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2}, "abc 123 גבא"},
+		{"אבג abc 123", bidi.LeftToRight, 0, []int{1, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0}, "גבא abc 123"},
+		// W4: a single separator between numbers
+		{"1-2 מכ", bidi.Auto, 1, []int{2, 2, 2, 1, 1, 1}, "כמ 1-2"},
+		// W2: European digits after an Arabic letter are Arabic numbers
+		{"عدد 12", bidi.Auto, 1, []int{1, 1, 1, 1, 2, 2}, "12 ددع"},
+		// N0 and L4: brackets take the direction of their content and are
+		// mirrored at right-to-left levels
+		{"(abc)", bidi.RightToLeft, 1, []int{1, 2, 2, 2, 1}, "(abc)"},
+		{"car (גדה) 1%", bidi.Auto, 0, []int{0, 0, 0, 0, 0, 1, 1, 1, 0, 0, 2, 2}, "car (הדג) 1%"},
+		{"אבג (abc) דהו", bidi.Auto, 1, []int{1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1}, "והד (abc) גבא"},
+		// X9: explicit embeddings are removed
+		{"\u202Eabc\u202C d", bidi.LeftToRight, 0, []int{-1, 1, 1, 1, -1, 0, 0}, "\u202E\u202Ccba d"},
+		// FSI takes the direction of the first strong character inside it
+		{"a \u2068אב c\u2069", bidi.Auto, 0, []int{0, 0, 0, 1, 1, 1, 2, 0}, "a \u2068c בא\u2069"},
+	}
+	for _, tc := range testCases {
+		p := bidi.Resolve([]rune(tc.text), tc.direction)
+		if p.Level != tc.level || !reflect.DeepEqual(p.Levels, tc.levels) {
+			t.Errorf("bidi.Resolve(%q) returns level %d and levels %v, but expected value is %d and %v", tc.text, p.Level, p.Levels, tc.level, tc.levels)
+		}
+		if visual := p.VisualString(); visual != tc.visual {
+			t.Errorf("Paragraph.VisualString of %q returns %q, but expected value is %q", tc.text, visual, tc.visual)
+		}
+	}
+}
+
+func TestClassName(t *testing.T) {
+	for c, expected := range map[rune]string{'a': "L", 'א': "R", 'ع': "AL", '1': "EN", '٣': "AN", ' ': "WS", '\u0301': "NSM", '\u2067': "RLI"} {
+		if name := bidi.ClassName(bidi.ClassOf(c)); name != expected {
+			t.Errorf("bidi.ClassName(bidi.ClassOf(%U)) returns %s, but expected value is %s", c, name, expected)
+		}
+	}
+}
+
+// classCharacters are characters of each Bidi_Class for the class sequences
+// of BidiTest.txt. None of them are brackets.
+var classCharacters = map[string]rune{
+	"L":   'a',
+	"R":   0x05D0,
+	"EN":  '1',
+	"ES":  '+',
+	"ET":  '$',
+	"AN":  0x0663,
+	"CS":  ',',
+	"B":   0x2029,
+	"S":   '\t',
+	"WS":  ' ',
+	"ON":  '!',
+	"BN":  0x00AD,
+	"NSM": 0x0301,
+	"AL":  0x0627,
+	"LRO": 0x202D,
+	"RLO": 0x202E,
+	"LRE": 0x202A,
+	"RLE": 0x202B,
+	"PDF": 0x202C,
+	"LRI": 0x2066,
+	"RLI": 0x2067,
+	"FSI": 0x2068,
+	"PDI": 0x2069,
+}
+
+// readTestData calls f with each line of a gzipped conformance test file
+// except comments and blank lines.
+func readTestData(t *testing.T, name string, f func(line string)) {
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("can't open %s (reason; %v)", name, err)
+	}
+	defer file.Close()
+	r, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("can't read %s (reason; %v)", name, err)
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" && !strings.HasPrefix(line, "#") {
+			f(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("can't read %s (reason; %v)", name, err)
+	}
+}
+
+// levelsString formats the levels of p as in the conformance test files,
+// where "x" is a character removed by rule X9.
+func levelsString(p *bidi.Paragraph) string {
+	levels := make([]string, len(p.Levels))
+	for i, level := range p.Levels {
+		levels[i] = "x"
+		if level >= 0 {
+			levels[i] = strconv.Itoa(level)
+		}
+	}
+	return strings.Join(levels, " ")
+}
+
+// orderString formats the visual order of p without the characters removed
+// by rule X9 as in the conformance test files.
+func orderString(p *bidi.Paragraph) string {
+	var order []string
+	for _, i := range p.VisualOrder() {
+		if p.Levels[i] >= 0 {
+			order = append(order, strconv.Itoa(i))
+		}
+	}
+	return strings.Join(order, " ")
+}
+
+func TestResolve_BidiTest(t *testing.T) {
+	var levels, order string
+	readTestData(t, "BidiTest.txt.gz", func(line string) {
+		switch {
+		case strings.HasPrefix(line, "@Levels:"):
+			levels = strings.TrimSpace(strings.TrimPrefix(line, "@Levels:"))
+			return
+		case strings.HasPrefix(line, "@Reorder:"):
+			order = strings.TrimSpace(strings.TrimPrefix(line, "@Reorder:"))
+			return
+		case strings.HasPrefix(line, "@"):
+			return
+		}
+		fields := strings.Split(line, ";")
+		var runes []rune
+		for _, class := range strings.Fields(fields[0]) {
+			runes = append(runes, classCharacters[class])
+		}
+		directions, _ := strconv.Atoi(strings.TrimSpace(fields[1]))
+		for i, direction := range []bidi.Direction{bidi.Auto, bidi.LeftToRight, bidi.RightToLeft} {
+			if directions&(1<<i) == 0 {
+				continue
+			}
+			p := bidi.Resolve(runes, direction)
+			if l, o := levelsString(p), orderString(p); l != levels || o != order {
+				t.Errorf("bidi.Resolve of %s in direction %d returns levels %q and order %q, but expected value is %q and %q", fields[0], direction, l, o, levels, order)
+			}
+		}
+	})
+}
+
+func TestResolve_BidiCharacterTest(t *testing.T) {
+	directions := map[string]bidi.Direction{"0": bidi.LeftToRight, "1": bidi.RightToLeft, "2": bidi.Auto}
+	readTestData(t, "BidiCharacterTest.txt.gz", func(line string) {
+		fields := strings.Split(line, ";")
+		var runes []rune
+		for _, field := range strings.Fields(fields[0]) {
+			c, _ := strconv.ParseUint(field, 16, 32)
+			runes = append(runes, rune(c))
+		}
+		p := bidi.Resolve(runes, directions[fields[1]])
+		if level, l, o := strconv.Itoa(p.Level), levelsString(p), orderString(p); level != fields[2] || l != fields[3] || o != fields[4] {
+			t.Errorf("bidi.Resolve of %s returns level %s, levels %q and order %q, but expected value is %s, %q and %q", fields[0], level, l, o, fields[2], fields[3], fields[4])
+		}
+	})
+}
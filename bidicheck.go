@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+
+	"github.com/moba1/usd/bidi"
+	"github.com/moba1/usd/unicode"
+	"golang.org/x/text/unicode/runenames"
+)
+
+const bidiCheckCmdName = "bidi-check"
+
+func parseBidiCheckCmd(args []string) func() error {
+	bidiCheckCmd := flag.NewFlagSet(bidiCheckCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(bidiCheckCmd)
+	bidiCheckCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", bidiCheckCmdName),
+			fmt.Sprintf("  %s [option] [file]...", bidiCheckCmdName),
+			"Standard input is checked when no file is given.",
+			"Exits with non-zero status when anything is found, including undecodable input.",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(bidiCheckCmd.Output(), stmt)
+		}
+		bidiCheckCmd.PrintDefaults()
+	}
+	if err := bidiCheckCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		table := newTableEncoder(tableOutput)
+		if !noHeader {
+			table.SetHeader([]string{"File", "Line", "Column", "Offset", "Code Point", "Name", "Finding"})
+		}
+		count := 0
+		// errUndecodable stops the check of a file which is reported as
+		// undecodable, and the next file is checked
+		errUndecodable := errors.New("undecodable")
+		check := func(fileName string, r io.Reader) error {
+			err := scanLines(r, cs, func(line []unicode.Char) error {
+				for _, c := range line {
+					if c.Err != nil {
+						table.Append([]string{
+							fileName,
+							strconv.Itoa(c.Line),
+							strconv.Itoa(c.Column),
+							strconv.FormatInt(c.Offset, 10),
+							"",
+							"<invalid>",
+							"Undecodable",
+						})
+						count++
+						return errUndecodable
+					}
+				}
+				for _, f := range bidi.Check(line) {
+					name := runenames.Name(f.Char.Rune)
+					if abbr, ok := bidi.ControlName(f.Char.Rune); ok {
+						name = fmt.Sprintf("%s (%s)", name, abbr)
+					}
+					table.Append([]string{
+						fileName,
+						strconv.Itoa(f.Char.Line),
+						strconv.Itoa(f.Char.Column),
+						strconv.FormatInt(f.Char.Offset, 10),
+						fmt.Sprintf("%U", f.Char.Rune),
+						name,
+						f.Kind.String(),
+					})
+					count++
+				}
+				return nil
+			})
+			if err == errUndecodable {
+				return nil
+			}
+			return err
+		}
+
+		err = readFiles(bidiCheckCmd.Args(), check)
+		if renderErr := table.Render(); err == nil {
+			err = renderErr
+		}
+		if err == nil && count > 0 {
+			err = fmt.Errorf("%s found %d problems", bidiCheckCmdName, count)
+		}
+		return err
+	}
+}
@@ -0,0 +1,226 @@
+package charset
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/moba1/usd/unicode"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// Charset reads and writes one character at a time in a character encoding.
+type Charset struct {
+	name   string
+	read   func(*bufio.Reader) (rune, []byte, error)
+	encode func(rune) ([]byte, error)
+	bom    []byte
+}
+
+type UnknownCharsetErr struct {
+	name string
+}
+
+func (e *UnknownCharsetErr) Error() string {
+	return fmt.Sprintf("unknown charset: %s", e.name)
+}
+
+type UnencodableErr struct {
+	char    rune
+	charset string
+}
+
+func (e *UnencodableErr) Error() string {
+	return fmt.Sprintf("%U can't be encoded in %s", e.char, e.charset)
+}
+
+func (e *UnencodableErr) Char() rune {
+	return e.char
+}
+
+var legacies = []struct {
+	name     string
+	encoding encoding.Encoding
+	maxLen   int
+}{
+	{name: "ShiftJIS", encoding: japanese.ShiftJIS, maxLen: 2},
+	{name: "EUCJP", encoding: japanese.EUCJP, maxLen: 3},
+	{name: "EUCKR", encoding: korean.EUCKR, maxLen: 2},
+	{name: "GBK", encoding: simplifiedchinese.GBK, maxLen: 2},
+	{name: "GB18030", encoding: simplifiedchinese.GB18030, maxLen: 4},
+	{name: "Latin1", encoding: charmap.ISO8859_1, maxLen: 1},
+	{name: "Windows1252", encoding: charmap.Windows1252, maxLen: 1},
+}
+
+// Names returns the names accepted by Lookup.
+func Names() []string {
+	names := []string{"UTF8", "UTF16", "UTF32"}
+	for _, l := range legacies {
+		names = append(names, l.name)
+	}
+	return names
+}
+
+// Lookup returns the charset called name. endian is used only by UTF16 and
+// UTF32.
+func Lookup(name string, endian unicode.Endian) (*Charset, error) {
+	switch name {
+	case "UTF8":
+		return &Charset{
+			name:   name,
+			read:   unicode.ReadUtf8Char,
+			encode: unicode.EncodeUtf8Char,
+			bom:    []byte{0xEF, 0xBB, 0xBF},
+		}, nil
+	case "UTF16":
+		bom, err := unicode.EncodeUtf16Char(endian, 0xFEFF)
+		if err != nil {
+			return nil, err
+		}
+		return &Charset{
+			name: name,
+			read: func(buf *bufio.Reader) (rune, []byte, error) {
+				return unicode.ReadUtf16Char(endian, buf)
+			},
+			encode: func(r rune) ([]byte, error) {
+				return unicode.EncodeUtf16Char(endian, r)
+			},
+			bom: bom,
+		}, nil
+	case "UTF32":
+		bom, err := unicode.EncodeUtf32Char(endian, 0xFEFF)
+		if err != nil {
+			return nil, err
+		}
+		return &Charset{
+			name: name,
+			read: func(buf *bufio.Reader) (rune, []byte, error) {
+				return unicode.ReadUtf32Char(endian, buf)
+			},
+			encode: func(r rune) ([]byte, error) {
+				return unicode.EncodeUtf32Char(endian, r)
+			},
+			bom: bom,
+		}, nil
+	}
+	for _, l := range legacies {
+		if l.name == name {
+			return newLegacyCharset(l.name, l.encoding, l.maxLen), nil
+		}
+	}
+	return nil, &UnknownCharsetErr{
+		name: name,
+	}
+}
+
+func (c *Charset) Name() string {
+	return c.name
+}
+
+// Read reads a character. It returns the same errors as the readers in the
+// unicode package.
+func (c *Charset) Read(buf *bufio.Reader) (rune, []byte, error) {
+	return c.read(buf)
+}
+
+// Encode returns the byte sequence of r, or UnencodableErr if the charset has
+// no representation of r.
+func (c *Charset) Encode(r rune) ([]byte, error) {
+	bs, err := c.encode(r)
+	if err != nil {
+		return nil, &UnencodableErr{
+			char:    r,
+			charset: c.name,
+		}
+	}
+	return bs, nil
+}
+
+// Label returns the name of the encoding with its endian as in the dump
+// records, such as "UTF-8", "UTF-16LE" or "ShiftJIS".
+func (c *Charset) Label() string {
+	switch c.name {
+	case "UTF8":
+		return "UTF-8"
+	case "UTF16", "UTF32":
+		// the little endian byte order marks begin with 0xFF
+		if c.bom[0] == 0xFF {
+			return "UTF-" + c.name[3:] + "LE"
+		}
+		return "UTF-" + c.name[3:] + "BE"
+	}
+	return c.name
+}
+
+// BOM returns the byte order mark, or nil if the charset has none.
+func (c *Charset) BOM() []byte {
+	return c.bom
+}
+
+// newLegacyCharset adapts a stateless x/text encoding. Bytes are fed to a fresh
+// decoder one at a time, so that each character keeps its own byte sequence.
+func newLegacyCharset(name string, enc encoding.Encoding, maxLen int) *Charset {
+	encodeRune := func(r rune) ([]byte, error) {
+		if !utf8.ValidRune(r) {
+			return nil, fmt.Errorf("invalid code point: %U", r)
+		}
+		return enc.NewEncoder().Bytes([]byte(string(r)))
+	}
+	read := func(buf *bufio.Reader) (rune, []byte, error) {
+		dst := make([]byte, 2*utf8.UTFMax)
+		for n := 1; ; n++ {
+			src, err := buf.Peek(n)
+			if err != nil && err != io.EOF {
+				return 0, nil, err
+			}
+			if len(src) == 0 {
+				return 0, nil, io.EOF
+			}
+			atEOF := len(src) < n
+			nDst, nSrc, err := enc.NewDecoder().Transform(dst, src, atEOF)
+			if err == transform.ErrShortSrc && !atEOF && n < maxLen {
+				continue
+			}
+			if nSrc == 0 {
+				bs := append([]byte{}, src...)
+				if _, err := buf.Discard(len(bs)); err != nil {
+					return 0, nil, err
+				}
+				if atEOF {
+					return 0, nil, unicode.NewUnexpectedEofErr(bs)
+				}
+				return 0, nil, unicode.NewInvalidSequenceErr(bs)
+			}
+			r, _ := utf8.DecodeRune(dst[:nDst])
+			// the decoders substitute U+FFFD for invalid input
+			invalid := false
+			if r == utf8.RuneError {
+				fffd, err := encodeRune(r)
+				invalid = err != nil || string(fffd) != string(src[:nSrc])
+			}
+			if invalid && nSrc > 1 && src[nSrc-1] < utf8.RuneSelf {
+				// an ASCII byte never belongs to a broken sequence
+				nSrc--
+			}
+			bs := append([]byte{}, src[:nSrc]...)
+			if _, err := buf.Discard(nSrc); err != nil {
+				return 0, nil, err
+			}
+			if invalid {
+				return 0, nil, unicode.NewInvalidSequenceErr(bs)
+			}
+			return r, bs, nil
+		}
+	}
+	return &Charset{
+		name:   name,
+		read:   read,
+		encode: encodeRune,
+	}
+}
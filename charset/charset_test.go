@@ -0,0 +1,136 @@
+package charset_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/unicode"
+)
+
+func TestLookup(t *testing.T) {
+	for _, name := range charset.Names() {
+		c, err := charset.Lookup(name, unicode.LittleEndian)
+		if err != nil {
+			t.Errorf("charset.Lookup(%q) returns error: %v", name, err)
+			continue
+		}
+		if c.Name() != name {
+			t.Errorf("charset.Lookup(%q) returns charset named %q", name, c.Name())
+		}
+	}
+
+	_, err := charset.Lookup("EBCDIC", unicode.BigEndian)
+	var unknownCharsetErr *charset.UnknownCharsetErr
+	if !errors.As(err, &unknownCharsetErr) {
+		t.Errorf("charset.Lookup return non-UnknownCharsetErr: %v", err)
+	}
+}
+
+func TestCharset_BOM(t *testing.T) {
+	testCases := []struct {
+		name   string
+		endian unicode.Endian
+		bom    []byte
+	}{
+		{name: "UTF8", endian: unicode.BigEndian, bom: []byte{0xEF, 0xBB, 0xBF}},
+		{name: "UTF16", endian: unicode.LittleEndian, bom: []byte{0xFF, 0xFE}},
+		{name: "UTF32", endian: unicode.BigEndian, bom: []byte{0x00, 0x00, 0xFE, 0xFF}},
+		{name: "ShiftJIS", endian: unicode.BigEndian, bom: nil},
+	}
+	for _, c := range testCases {
+		cs, err := charset.Lookup(c.name, c.endian)
+		if err != nil {
+			t.Fatalf("charset.Lookup(%q) returns error: %v", c.name, err)
+		}
+		if !reflect.DeepEqual(cs.BOM(), c.bom) {
+			t.Errorf("%s BOM is %v, but expected value is %v", c.name, cs.BOM(), c.bom)
+		}
+	}
+}
+
+func TestCharset_Label(t *testing.T) {
+	testCases := []struct {
+		name   string
+		endian unicode.Endian
+		label  string
+	}{
+		{name: "UTF8", endian: unicode.BigEndian, label: "UTF-8"},
+		{name: "UTF16", endian: unicode.LittleEndian, label: "UTF-16LE"},
+		{name: "UTF32", endian: unicode.BigEndian, label: "UTF-32BE"},
+		{name: "UTF32", endian: unicode.LittleEndian, label: "UTF-32LE"},
+		{name: "ShiftJIS", endian: unicode.BigEndian, label: "ShiftJIS"},
+	}
+	for _, c := range testCases {
+		cs, err := charset.Lookup(c.name, c.endian)
+		if err != nil {
+			t.Fatalf("charset.Lookup(%q) returns error: %v", c.name, err)
+		}
+		if cs.Label() != c.label {
+			t.Errorf("%s label is %q, but expected value is %q", c.name, cs.Label(), c.label)
+		}
+	}
+}
+
+func TestCharset_Read_Legacy(t *testing.T) {
+	cs, err := charset.Lookup("ShiftJIS", unicode.BigEndian)
+	if err != nil {
+		t.Fatalf("charset.Lookup returns error: %v", err)
+	}
+	buf := bufio.NewReader(bytes.NewBuffer([]byte{0x82, 0xA0, 0x41, 0xFF, 0x82, 0x20}))
+
+	r, bs, err := cs.Read(buf)
+	if err != nil || r != 'あ' || !reflect.DeepEqual(bs, []byte{0x82, 0xA0}) {
+		t.Errorf("Charset.Read returns (%U, %v, %v), but expected value is (U+3042, [0x82 0xA0], nil)", r, bs, err)
+	}
+	r, bs, err = cs.Read(buf)
+	if err != nil || r != 'A' || !reflect.DeepEqual(bs, []byte{0x41}) {
+		t.Errorf("Charset.Read returns (%U, %v, %v), but expected value is (U+0041, [0x41], nil)", r, bs, err)
+	}
+	for _, expected := range [][]byte{{0xFF}, {0x82}} {
+		_, _, err = cs.Read(buf)
+		var invalidSequenceErr *unicode.InvalidSequenceErr
+		if !errors.As(err, &invalidSequenceErr) {
+			t.Errorf("Charset.Read return non-InvalidSequenceErr: %v", err)
+		} else if !reflect.DeepEqual(invalidSequenceErr.Sequences(), expected) {
+			t.Errorf("Charset.Read returns invalid sequences %v, but expected value is %v", invalidSequenceErr.Sequences(), expected)
+		}
+	}
+	r, _, err = cs.Read(buf)
+	if err != nil || r != ' ' {
+		t.Errorf("Charset.Read returns (%U, %v), but expected value is (U+0020, nil)", r, err)
+	}
+	_, _, err = cs.Read(buf)
+	if err != io.EOF {
+		t.Errorf("expected EOF, but Charset.Read returns %v", err)
+	}
+}
+
+func TestCharset_Encode(t *testing.T) {
+	cs, err := charset.Lookup("Latin1", unicode.BigEndian)
+	if err != nil {
+		t.Fatalf("charset.Lookup returns error: %v", err)
+	}
+	bs, err := cs.Encode('é')
+	if err != nil || !reflect.DeepEqual(bs, []byte{0xE9}) {
+		t.Errorf("Charset.Encode returns (%v, %v), but expected value is ([0xE9], nil)", bs, err)
+	}
+	_, err = cs.Encode('あ')
+	var unencodableErr *charset.UnencodableErr
+	if !errors.As(err, &unencodableErr) {
+		t.Errorf("Charset.Encode return non-UnencodableErr: %v", err)
+	}
+
+	cs, err = charset.Lookup("UTF16", unicode.BigEndian)
+	if err != nil {
+		t.Fatalf("charset.Lookup returns error: %v", err)
+	}
+	_, err = cs.Encode(0xD800)
+	if !errors.As(err, &unencodableErr) {
+		t.Errorf("Charset.Encode return non-UnencodableErr: %v", err)
+	}
+}
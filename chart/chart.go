@@ -0,0 +1,147 @@
+// Package chart renders code charts: grids of 16 code points per row, like
+// the charts published by the Unicode Consortium.
+package chart
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/moba1/usd/ucd"
+	"golang.org/x/text/unicode/runenames"
+)
+
+type Format int
+
+const (
+	Text Format = iota
+	HTML
+	SVG
+)
+
+type Kind int
+
+const (
+	Graphic Kind = iota
+	Combining
+	Control
+	Invisible
+	PrivateUse
+	Surrogate
+	Noncharacter
+	Unassigned
+	// Outside is a cell of a grid row that is not in the requested range.
+	Outside
+)
+
+func (k Kind) String() string {
+	return [...]string{"graphic", "combining", "control", "invisible", "private-use", "surrogate", "noncharacter", "unassigned", "outside"}[k]
+}
+
+type Cell struct {
+	Char rune
+	Kind Kind
+	// Label is what the cell shows: the character, or an abbreviation for
+	// characters without a visible glyph.
+	Label string
+	Name  string
+}
+
+// IsNoncharacter reports whether c is one of the 66 noncharacters.
+func IsNoncharacter(c rune) bool {
+	return (0xFDD0 <= c && c <= 0xFDEF) || c&0xFFFE == 0xFFFE
+}
+
+func abbreviation(c rune) string {
+	for _, alias := range ucd.Aliases(c) {
+		if alias.Type == "abbreviation" {
+			return alias.Name
+		}
+	}
+	return ""
+}
+
+func name(c rune) string {
+	if n := ucd.Name(c); n != "" {
+		return n
+	}
+	if aliases := ucd.Aliases(c); len(aliases) > 0 {
+		return aliases[0].Name
+	}
+	return runenames.Name(c)
+}
+
+func NewCell(c rune) Cell {
+	cell := Cell{Char: c, Label: string(c), Name: name(c)}
+	switch category := ucd.Category(c); {
+	case IsNoncharacter(c):
+		cell.Kind, cell.Label = Noncharacter, "NC"
+	case category == "Cn":
+		cell.Kind, cell.Label = Unassigned, ""
+	case category == "Cc":
+		cell.Kind, cell.Label = Control, abbreviation(c)
+	case category == "Cs":
+		cell.Kind, cell.Label = Surrogate, "SUR"
+	case category == "Co":
+		cell.Kind, cell.Label = PrivateUse, "PUA"
+	case category == "Cf", category == "Zl", category == "Zp", category == "Zs", unicode.Is(unicode.Variation_Selector, c):
+		cell.Kind = Invisible
+		if a := abbreviation(c); a != "" {
+			cell.Label = a
+		} else {
+			cell.Label = fmt.Sprintf("%04X", c)
+		}
+	case strings.HasPrefix(category, "M"):
+		// shown on a dotted circle as in the code charts
+		cell.Kind, cell.Label = Combining, "◌"+string(c)
+	}
+	return cell
+}
+
+// Rows returns the cells of first..last, padded with Outside cells to whole
+// rows of 16.
+func Rows(first, last rune) [][]Cell {
+	var rows [][]Cell
+	for base := first &^ 0xF; base <= last; base += 0x10 {
+		row := make([]Cell, 16)
+		for i := range row {
+			c := base + rune(i)
+			if c < first || last < c {
+				row[i] = Cell{Char: c, Kind: Outside}
+			} else {
+				row[i] = NewCell(c)
+			}
+		}
+		rows = append(rows, row)
+		if base+0x10 < base {
+			break
+		}
+	}
+	return rows
+}
+
+func Render(w io.Writer, format Format, first, last rune) error {
+	rows := Rows(first, last)
+	var err error
+	switch format {
+	case Text:
+		err = renderText(w, rows)
+	case HTML:
+		err = renderHTML(w, rows, first, last)
+	case SVG:
+		err = renderSVG(w, rows, first, last)
+	default:
+		return fmt.Errorf("unknown chart format: %d", format)
+	}
+	if err != nil {
+		return fmt.Errorf("can't write chart (reason; %s)", err.Error())
+	}
+	return nil
+}
+
+// rowLabel is the code point of the row without its last hex digit, e.g.
+// "U+304x".
+func rowLabel(base rune) string {
+	return fmt.Sprintf("U+%0*Xx", len(fmt.Sprintf("%04X", base))-1, base>>4)
+}
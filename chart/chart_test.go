@@ -0,0 +1,108 @@
+package chart_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/moba1/usd/chart"
+)
+
+func TestNewCell(t *testing.T) {
+	testCases := []struct {
+		char  rune
+		kind  chart.Kind
+		label string
+	}{
+		{char: 'あ', kind: chart.Graphic, label: "あ"},
+		{char: 0x0301, kind: chart.Combining, label: "◌́"},
+		{char: 0x0000, kind: chart.Control, label: "NUL"},
+		{char: 0x200B, kind: chart.Invisible, label: "ZWSP"},
+		{char: 0xE000, kind: chart.PrivateUse, label: "PUA"},
+		{char: 0xD800, kind: chart.Surrogate, label: "SUR"},
+		{char: 0xFDD0, kind: chart.Noncharacter, label: "NC"},
+		{char: 0x1FFFE, kind: chart.Noncharacter, label: "NC"},
+		{char: 0x0378, kind: chart.Unassigned, label: ""},
+	}
+	for _, c := range testCases {
+		cell := chart.NewCell(c.char)
+		if cell.Kind != c.kind || cell.Label != c.label {
+			t.Errorf("chart.NewCell(%U) returns (%v, %q), but expected value is (%v, %q)", c.char, cell.Kind, cell.Label, c.kind, c.label)
+		}
+	}
+}
+
+func TestRows(t *testing.T) {
+	rows := chart.Rows(0x3041, 0x3052)
+	if len(rows) != 2 {
+		t.Fatalf("chart.Rows returns %d rows, but expected value is 2", len(rows))
+	}
+	if rows[0][0].Char != 0x3040 || rows[0][0].Kind != chart.Outside {
+		t.Errorf("first cell is %v, but expected value is U+3040 outside the range", rows[0][0])
+	}
+	if rows[0][1].Char != 0x3041 || rows[0][1].Kind != chart.Graphic {
+		t.Errorf("second cell is %v, but expected value is U+3041", rows[0][1])
+	}
+	if rows[1][3].Kind != chart.Outside {
+		t.Errorf("U+3053 is in the chart: %v", rows[1][3])
+	}
+}
+
+func TestRender_Text(t *testing.T) {
+	var buf bytes.Buffer
+	if err := chart.Render(&buf, chart.Text, 0x3040, 0x309F); err != nil {
+		t.Fatalf("chart.Render returns error: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasPrefix(lines[1], "U+304x ....    ぁ") {
+		t.Errorf("first row is %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[6], "U+309x ゐ") {
+		t.Errorf("last row is %q", lines[6])
+	}
+}
+
+func TestRender_TextUTF8(t *testing.T) {
+	for _, r := range [][2]rune{{0x0300, 0x036F}, {0x2000, 0x206F}, {0xFE00, 0xFE0F}} {
+		var buf bytes.Buffer
+		if err := chart.Render(&buf, chart.Text, r[0], r[1]); err != nil {
+			t.Fatalf("chart.Render returns error: %v", err)
+		}
+		if !utf8.Valid(buf.Bytes()) {
+			t.Errorf("chart.Render of %U..%U writes invalid UTF-8: %q", r[0], r[1], buf.String())
+		}
+	}
+}
+
+func TestRender_HTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := chart.Render(&buf, chart.HTML, 0xFDD0, 0xFDDF); err != nil {
+		t.Fatalf("chart.Render returns error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<td class="noncharacter" title="U+FDD0`) {
+		t.Errorf("noncharacter isn't marked: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "http") {
+		t.Errorf("chart refers external assets: %s", buf.String())
+	}
+}
+
+func TestRender_SVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := chart.Render(&buf, chart.SVG, 0x0000, 0x007F); err != nil {
+		t.Fatalf("chart.Render returns error: %v", err)
+	}
+	decoder := xml.NewDecoder(&buf)
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("chart.Render writes invalid SVG: %v", err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+)
+
+const htmlStyle = `table { border-collapse: collapse; font-family: sans-serif; }
+th { font-weight: normal; color: #555; padding: 2px 6px; }
+td { border: 1px solid #999; width: 3em; height: 3em; text-align: center; vertical-align: middle; }
+td .glyph { display: block; font-size: 1.6em; }
+td .code { display: block; font-size: 0.6em; color: #555; }
+td.control .glyph, td.invisible .glyph, td.private-use .glyph, td.surrogate .glyph { font-size: 0.8em; border: 1px dashed #555; padding: 2px; }
+td.noncharacter { background: repeating-linear-gradient(45deg, #fff, #fff 4px, #ccc 4px, #ccc 8px); }
+td.unassigned { background: #ccc; }
+td.outside { border: none; }`
+
+func renderHTML(w io.Writer, rows [][]Cell, first, last rune) error {
+	title := fmt.Sprintf("U+%04X..U+%04X", first, last)
+	if _, err := fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n<table>\n<tr><th></th>", title, htmlStyle); err != nil {
+		return err
+	}
+	for i := 0; i < 16; i++ {
+		if _, err := fmt.Fprintf(w, "<th>%X</th>", i); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(w, "</tr>"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "<tr><th>%s</th>", rowLabel(row[0].Char)); err != nil {
+			return err
+		}
+		for _, cell := range row {
+			var err error
+			switch cell.Kind {
+			case Outside:
+				_, err = fmt.Fprintf(w, "<td class=\"%s\"></td>", cell.Kind)
+			case Unassigned:
+				_, err = fmt.Fprintf(w, "<td class=\"%s\" title=\"%U unassigned\"></td>", cell.Kind, cell.Char)
+			default:
+				_, err = fmt.Fprintf(w, "<td class=\"%s\" title=\"%U %s\"><span class=\"glyph\">%s</span><span class=\"code\">%04X</span></td>",
+					cell.Kind, cell.Char, html.EscapeString(cell.Name), html.EscapeString(cell.Label), cell.Char)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, "</tr>"); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "</table>\n</body>\n</html>")
+	return err
+}
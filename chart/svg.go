@@ -0,0 +1,77 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+)
+
+const (
+	svgCellSize     = 48
+	svgLabelWidth   = 72
+	svgHeaderHeight = 24
+)
+
+func renderSVG(w io.Writer, rows [][]Cell, first, last rune) error {
+	width := svgLabelWidth + 16*svgCellSize
+	height := svgHeaderHeight + len(rows)*svgCellSize
+	if _, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">
+<title>U+%04X..U+%04X</title>
+<defs><pattern id="noncharacter" width="8" height="8" patternUnits="userSpaceOnUse" patternTransform="rotate(45)"><rect width="4" height="8" fill="#ccc"/></pattern></defs>
+<rect width="100%%" height="100%%" fill="#fff"/>
+`, width, height, width, height, first, last); err != nil {
+		return err
+	}
+	for i := 0; i < 16; i++ {
+		x := svgLabelWidth + i*svgCellSize + svgCellSize/2
+		if _, err := fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\" font-size=\"14\" fill=\"#555\">%X</text>\n", x, svgHeaderHeight-6, i); err != nil {
+			return err
+		}
+	}
+	for j, row := range rows {
+		y := svgHeaderHeight + j*svgCellSize
+		if _, err := fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" text-anchor=\"end\" font-size=\"12\" fill=\"#555\">%s</text>\n", svgLabelWidth-6, y+svgCellSize/2+4, rowLabel(row[0].Char)); err != nil {
+			return err
+		}
+		for i, cell := range row {
+			if err := renderSVGCell(w, cell, svgLabelWidth+i*svgCellSize, y); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+func renderSVGCell(w io.Writer, cell Cell, x, y int) error {
+	if cell.Kind == Outside {
+		return nil
+	}
+	fill := "#fff"
+	switch cell.Kind {
+	case Unassigned:
+		fill = "#ccc"
+	case Noncharacter:
+		fill = "url(#noncharacter)"
+	}
+	if _, err := fmt.Fprintf(w, "<g><title>%U %s</title><rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" stroke=\"#999\"/>",
+		cell.Char, html.EscapeString(cell.Name), x, y, svgCellSize, svgCellSize, fill); err != nil {
+		return err
+	}
+	switch cell.Kind {
+	case Unassigned:
+	case Control, Invisible, PrivateUse, Surrogate:
+		// dashed box as in the code charts
+		if _, err := fmt.Fprintf(w, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"none\" stroke=\"#555\" stroke-dasharray=\"2\"/><text x=\"%d\" y=\"%d\" text-anchor=\"middle\" font-size=\"10\">%s</text>",
+			x+6, y+8, svgCellSize-12, svgCellSize-24, x+svgCellSize/2, y+svgCellSize/2-2, html.EscapeString(cell.Label)); err != nil {
+			return err
+		}
+	default:
+		if _, err := fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\" font-size=\"22\">%s</text>",
+			x+svgCellSize/2, y+svgCellSize/2+2, html.EscapeString(cell.Label)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\" font-size=\"9\" fill=\"#555\">%04X</text></g>\n", x+svgCellSize/2, y+svgCellSize-4, cell.Char)
+	return err
+}
@@ -0,0 +1,62 @@
+package chart
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/moba1/usd/ucd"
+)
+
+const textCellWidth = 8
+
+func textLabel(cell Cell) string {
+	switch cell.Kind {
+	case Outside:
+		return ""
+	case Control:
+		return "<" + cell.Label + ">"
+	case Invisible:
+		return "[" + cell.Label + "]"
+	case Noncharacter:
+		return "(" + cell.Label + ")"
+	case Unassigned:
+		return "...."
+	}
+	return cell.Label
+}
+
+// truncate cuts s to at most width columns without splitting a character.
+func truncate(s string, width int) string {
+	w := 0
+	for i, c := range s {
+		if w += ucd.DisplayWidth(string(c)); w > width {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func renderText(w io.Writer, rows [][]Cell) error {
+	labelWidth := len(rowLabel(rows[len(rows)-1][0].Char))
+	header := strings.Repeat(" ", labelWidth)
+	for i := 0; i < 16; i++ {
+		header += fmt.Sprintf(" %-*X", textCellWidth-1, i)
+	}
+	if _, err := fmt.Fprintln(w, strings.TrimRight(header, " ")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		line := fmt.Sprintf("%-*s", labelWidth, rowLabel(row[0].Char))
+		for _, cell := range row {
+			label := textLabel(cell)
+			label = truncate(label, textCellWidth-1)
+			line += " " + label + strings.Repeat(" ", textCellWidth-1-ucd.DisplayWidth(label))
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "<XXX> control  [XXX] invisible  (NC) noncharacter  .... unassigned  PUA private use  SUR surrogate")
+	return err
+}
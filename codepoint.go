@@ -0,0 +1,185 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/chart"
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/ucd"
+)
+
+const (
+	cpCmdName    = "cp"
+	rangeCmdName = "range"
+)
+
+// codePointTable writes the records of code points encoded in a charset as
+// soon as they are appended. Offsets are those of the code points written one
+// after another. Code points which the charset can't encode, such as
+// surrogates, are written with the error.
+type codePointTable struct {
+	table  encoder.TableEncoder
+	cs     *charset.Charset
+	offset int64
+}
+
+func newCodePointTable(cs *charset.Charset) *codePointTable {
+	table := newTableEncoder(tableOutput)
+	if !noHeader {
+		table.SetHeader(dumpHeader)
+	}
+	return &codePointTable{table: table, cs: cs}
+}
+
+func (cpt *codePointTable) append(c rune) {
+	bs, err := cpt.cs.Encode(c)
+	cpt.table.AppendRecord(encoder.CharRecord{Rune: c, Bytes: bs, Offset: cpt.offset, Encoding: cpt.cs.Label(), Err: err})
+	cpt.offset += int64(len(bs))
+}
+
+func (cpt *codePointTable) render() error {
+	return cpt.table.Render()
+}
+
+func parseCpCmd(args []string) func() error {
+	cpCmd := flag.NewFlagSet(cpCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(cpCmd)
+	cpCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", cpCmdName),
+			fmt.Sprintf("  %s [option] <code point>...", cpCmdName),
+			"Code point:",
+			"  U+1F427, 0x1F427 or the character itself",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(cpCmd.Output(), stmt)
+		}
+		cpCmd.PrintDefaults()
+	}
+	if err := cpCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+	if cpCmd.NArg() == 0 {
+		cpCmd.Usage()
+		os.Exit(2)
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		var codePoints []rune
+		for _, arg := range cpCmd.Args() {
+			c, err := ucd.ParseCodePoint(arg)
+			if err != nil {
+				return err
+			}
+			codePoints = append(codePoints, c)
+		}
+		table := newCodePointTable(cs)
+		for _, c := range codePoints {
+			table.append(c)
+		}
+		return table.render()
+	}
+}
+
+func parseRangeCmd(args []string) func() error {
+	rangeCmd := flag.NewFlagSet(rangeCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(rangeCmd)
+	var (
+		block       string
+		chartFormat *chart.Format
+	)
+	rangeCmd.StringVar(&block, "block", "", "list code points in `block` instead of range")
+	rangeCmd.Func("chart", "render a code chart in `format` instead of a table (value: Text|HTML|SVG)", func(s string) error {
+		var f chart.Format
+		switch s {
+		case "Text":
+			f = chart.Text
+		case "HTML":
+			f = chart.HTML
+		case "SVG":
+			f = chart.SVG
+		default:
+			return fmt.Errorf("invalid chart format: %s", s)
+		}
+		chartFormat = &f
+		return nil
+	})
+	rangeCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", rangeCmdName),
+			fmt.Sprintf("  %s [option] <range>...", rangeCmdName),
+			fmt.Sprintf("  %s [option] -block <block>", rangeCmdName),
+			"Range:",
+			"  U+3040..U+309F",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(rangeCmd.Output(), stmt)
+		}
+		rangeCmd.PrintDefaults()
+	}
+	if err := rangeCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+	if rangeCmd.NArg() == 0 && block == "" {
+		rangeCmd.Usage()
+		os.Exit(2)
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		type bounds struct {
+			first rune
+			last  rune
+		}
+		var ranges []bounds
+		if block != "" {
+			b, err := ucd.LookupBlock(block)
+			if err != nil {
+				return err
+			}
+			ranges = append(ranges, bounds{first: b.First, last: b.Last})
+		}
+		for _, arg := range rangeCmd.Args() {
+			first, last, err := ucd.ParseRange(arg)
+			if err != nil {
+				return err
+			}
+			ranges = append(ranges, bounds{first: first, last: last})
+		}
+		if chartFormat != nil {
+			if len(ranges) != 1 {
+				return fmt.Errorf("chart needs exactly one range")
+			}
+			w, flush := newTextOutput()
+			err := chart.Render(w, *chartFormat, ranges[0].first, ranges[0].last)
+			if flushErr := flush(); err == nil {
+				err = flushErr
+			}
+			return err
+		}
+		table := newCodePointTable(cs)
+		for _, r := range ranges {
+			for c := r.first; c <= r.last; c++ {
+				table.append(c)
+			}
+		}
+		return table.render()
+	}
+}
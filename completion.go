@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/moba1/usd/charset"
+)
+
+const completionCmdName = "completion"
+
+// subCmdNames are completed as the first argument.
+var subCmdNames = []string{
+	"utf8", "utf16", "utf32", transcodeCmdName, searchCmdName, cpCmdName, rangeCmdName,
+	securityCmdName, bidiCheckCmdName, bidiCmdName, whitespaceCmdName, hiddenCmdName,
+	sanitizeCmdName, grepCmdName, completionCmdName,
+}
+
+func parseCompletionCmd(args []string) func() error {
+	completionCmd := flag.NewFlagSet(completionCmdName, flag.ExitOnError)
+	shell := "Bash"
+	completionCmd.Func("shell", "`shell` of the script. default is 'Bash' (value: Bash|Zsh)", func(s string) error {
+		switch s {
+		case "Bash", "Zsh":
+			shell = s
+		default:
+			return fmt.Errorf("invalid shell: %s", s)
+		}
+		return nil
+	})
+	completionCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", completionCmdName),
+			fmt.Sprintf("  %s [option]", completionCmdName),
+			"Prints a script completing the sub commands, the options and the file types.",
+			"  bash: source <(usd completion)",
+			"  zsh:  source <(usd completion -shell Zsh)",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(completionCmd.Output(), stmt)
+		}
+		completionCmd.PrintDefaults()
+	}
+	if err := completionCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+
+	return func() error {
+		var options, valueOptions []string
+		flag.CommandLine.VisitAll(func(f *flag.Flag) {
+			options = append(options, "-"+f.Name)
+			if b, ok := f.Value.(interface{ IsBoolFlag() bool }); !ok || !b.IsBoolFlag() {
+				valueOptions = append(valueOptions, "-"+f.Name)
+			}
+		})
+		options = append(options, "-help")
+		prog := path.Base(os.Args[0])
+		script := completionScript(prog, subCmdNames, options, valueOptions, fileTypeNames(), charset.Names())
+		if shell == "Zsh" {
+			script = "autoload -U +X bashcompinit && bashcompinit\n" + script
+		}
+		_, err := fmt.Fprint(tableOutput, script)
+		return err
+	}
+}
+
+// completionScript returns a bash completion function of prog. zsh reads it
+// through bashcompinit. The words after valueOptions are skipped when looking
+// for the sub command.
+func completionScript(prog string, subCmds, options, valueOptions, fileTypes, charsets []string) string {
+	funcName := "_" + strings.NewReplacer("-", "_", ".", "_").Replace(prog)
+	lines := []string{
+		fmt.Sprintf("%s() {", funcName),
+		`	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"`,
+		`	case "$prev" in`,
+		fmt.Sprintf(`	-fileType) COMPREPLY=($(compgen -W "%s" -- "$cur")); return ;;`, strings.Join(fileTypes, " ")),
+		fmt.Sprintf(`	-outputEncoding|-encoding) COMPREPLY=($(compgen -W "%s" -- "$cur")); return ;;`, strings.Join(charsets, " ")),
+		`	-outputEndian|-endian) COMPREPLY=($(compgen -W "Big Little" -- "$cur")); return ;;`,
+		`	-o|-template) COMPREPLY=($(compgen -f -- "$cur")); return ;;`,
+		`	esac`,
+		`	local i`,
+		`	for ((i = 1; i < COMP_CWORD; i++)); do`,
+		`		case "${COMP_WORDS[i]}" in`,
+		fmt.Sprintf(`		%s) ((i++)) ;;`, strings.Join(valueOptions, "|")),
+		`		-*) ;;`,
+		`		*) COMPREPLY=($(compgen -f -- "$cur")); return ;;`,
+		`		esac`,
+		`	done`,
+		`	case "$cur" in`,
+		fmt.Sprintf(`	-*) COMPREPLY=($(compgen -W "%s" -- "$cur")) ;;`, strings.Join(options, " ")),
+		fmt.Sprintf(`	*) COMPREPLY=($(compgen -W "%s" -- "$cur")) ;;`, strings.Join(subCmds, " ")),
+		`	esac`,
+		`}`,
+		fmt.Sprintf("complete -F %s %s", funcName, prog),
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,95 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AsciiDocTableEncoder writes an AsciiDoc table. Each row is written as soon
+// as it is appended.
+type AsciiDocTableEncoder struct {
+	writer  io.Writer
+	header  []string
+	started bool
+	err     error
+}
+
+func NewAsciiDocTableEncoder(w io.Writer) *AsciiDocTableEncoder {
+	return &AsciiDocTableEncoder{
+		writer: w,
+	}
+}
+
+func (ate *AsciiDocTableEncoder) SetHeader(h []string) {
+	ate.header = h
+}
+
+// asciiDocReplacer replaces the characters of the AsciiDoc syntax with
+// character references, since a backslash doesn't escape all of them.
+var asciiDocReplacer = newEscapeReplacer("|*_`#^~[]{}\\<>&'\"", func(c rune) string {
+	return fmt.Sprintf("&#%d;", c)
+})
+
+func (ate *AsciiDocTableEncoder) write(s string) {
+	if ate.err != nil {
+		return
+	}
+	if _, err := io.WriteString(ate.writer, s); err != nil {
+		ate.err = fmt.Errorf("can't write asciidoc table (reason; %s)", err.Error())
+	}
+}
+
+func (ate *AsciiDocTableEncoder) writeRow(row []string) {
+	var b strings.Builder
+	for i, cell := range row {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString("|" + asciiDocReplacer.Replace(singleLine(cell)))
+	}
+	b.WriteString("\n")
+	ate.write(b.String())
+}
+
+// start writes the beginning of the table with the number of columns of the
+// header or the first row. It reports whether the table is begun.
+func (ate *AsciiDocTableEncoder) start(columns int) bool {
+	if ate.started {
+		return true
+	}
+	n := len(ate.header)
+	if n == 0 {
+		n = columns
+	}
+	if n == 0 {
+		return false
+	}
+	ate.started = true
+	if len(ate.header) > 0 {
+		ate.write(fmt.Sprintf("[cols=\"%d*\",options=\"header\"]\n|===\n", n))
+		ate.writeRow(ate.header)
+		// an empty line after the first line makes it the header
+		ate.write("\n")
+	} else {
+		ate.write(fmt.Sprintf("[cols=\"%d*\"]\n|===\n", n))
+	}
+	return true
+}
+
+func (ate *AsciiDocTableEncoder) Append(row []string) {
+	ate.start(len(row))
+	ate.writeRow(row)
+}
+
+func (ate *AsciiDocTableEncoder) AppendRecord(r CharRecord) {
+	ate.Append(r.Row())
+}
+
+// Render ends the table and returns the first error of Append.
+func (ate *AsciiDocTableEncoder) Render() error {
+	if ate.start(0) {
+		ate.write("|===\n")
+	}
+	return ate.err
+}
@@ -0,0 +1,30 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+func TestAsciiDocTableEncoder_Render(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ate := encoder.NewAsciiDocTableEncoder(buf)
+	ate.SetHeader([]string{"Character", "Code Point"})
+	ate.Append([]string{"|", "U+007C"})
+	ate.Append([]string{"*", "U+002A"})
+	if err := ate.Render(); err != nil {
+		t.Fatalf("error occured at AsciiDocTableEncoder.Render (%v)", err)
+	}
+
+	expected := "[cols=\"2*\",options=\"header\"]\n" +
+		"|===\n" +
+		"|Character |Code Point\n" +
+		"\n" +
+		"|&#124; |U+007C\n" +
+		"|&#42; |U+002A\n" +
+		"|===\n"
+	if buf.String() != expected {
+		t.Errorf("expected AsciiDoc table: %q, but AsciiDocTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,59 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+)
+
+// binaryEncoder writes each row as a data item of a binary format as soon as
+// it is appended. The rows are the same as the JSON records: maps whose keys
+// are the header in lower camel case, or arrays without header.
+type binaryEncoder struct {
+	writer io.Writer
+	// name is the name of the format in the errors
+	name   string
+	header []string
+	append func(buf []byte, v interface{}) ([]byte, error)
+	err    error
+}
+
+func (be *binaryEncoder) SetHeader(h []string) {
+	be.header = h
+}
+
+func (be *binaryEncoder) Append(row []string) {
+	be.write(jsonRecord{header: be.header, row: row}.value())
+}
+
+// AppendRecord writes r as a map, or as an array of the values of the map
+// without header.
+func (be *binaryEncoder) AppendRecord(r CharRecord) {
+	object := charRecordObject(r, be.header)
+	if len(be.header) > 0 {
+		be.write(object)
+		return
+	}
+	values := make([]interface{}, len(object))
+	for i, field := range object {
+		values[i] = field.value
+	}
+	be.write(values)
+}
+
+func (be *binaryEncoder) write(v interface{}) {
+	if be.err != nil {
+		return
+	}
+	bs, err := be.append(nil, v)
+	if err == nil {
+		_, err = be.writer.Write(bs)
+	}
+	if err != nil {
+		be.err = fmt.Errorf("can't write %s record (reason; %s)", be.name, err.Error())
+	}
+}
+
+// Render returns the first error of Append.
+func (be *binaryEncoder) Render() error {
+	return be.err
+}
@@ -0,0 +1,107 @@
+package encoder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// CBORTableEncoder writes the rows as a CBOR sequence (RFC 8742), one data
+// item for each row. Code points and numbers are integers and bytes are byte
+// strings.
+type CBORTableEncoder struct {
+	binaryEncoder
+}
+
+func NewCBORTableEncoder(w io.Writer) *CBORTableEncoder {
+	return &CBORTableEncoder{
+		binaryEncoder{writer: w, name: "cbor", append: appendCBOR},
+	}
+}
+
+// the major types of CBOR
+const (
+	cborUnsigned byte = iota << 5
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+const cborNull = cborSimple | 22
+
+// appendCBORHead appends the initial byte of the major type and its argument
+// n in the shortest form.
+func appendCBORHead(buf []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= 0xFF:
+		return append(buf, major|24, byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, major|25, 0, 0)
+		binary.BigEndian.PutUint16(buf[len(buf)-2:], uint16(n))
+	case n <= 0xFFFFFFFF:
+		buf = append(buf, major|26, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(buf[len(buf)-4:], uint32(n))
+	default:
+		buf = append(buf, major|27, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(buf[len(buf)-8:], n)
+	}
+	return buf
+}
+
+func appendCBORInt(buf []byte, n int64) []byte {
+	if n < 0 {
+		return appendCBORHead(buf, cborNegative, uint64(-(n + 1)))
+	}
+	return appendCBORHead(buf, cborUnsigned, uint64(n))
+}
+
+// appendCBOR appends v, which is a value of jsonRecord or charRecordObject.
+func appendCBOR(buf []byte, v interface{}) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		buf = append(buf, cborNull)
+	case string:
+		buf = appendCBORHead(buf, cborText, uint64(len(v)))
+		buf = append(buf, v...)
+	case byteString:
+		buf = appendCBORHead(buf, cborBytes, uint64(len(v)))
+		buf = append(buf, v...)
+	case int:
+		buf = appendCBORInt(buf, int64(v))
+	case int32:
+		buf = appendCBORInt(buf, int64(v))
+	case int64:
+		buf = appendCBORInt(buf, v)
+	case []int64:
+		buf = appendCBORHead(buf, cborArray, uint64(len(v)))
+		for _, n := range v {
+			buf = appendCBORInt(buf, n)
+		}
+	case []interface{}:
+		buf = appendCBORHead(buf, cborArray, uint64(len(v)))
+		for _, value := range v {
+			if buf, err = appendCBOR(buf, value); err != nil {
+				return nil, err
+			}
+		}
+	case jsonObject:
+		buf = appendCBORHead(buf, cborMap, uint64(len(v)))
+		for _, field := range v {
+			buf = appendCBORHead(buf, cborText, uint64(len(field.key)))
+			buf = append(buf, field.key...)
+			if buf, err = appendCBOR(buf, field.value); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+	return buf, nil
+}
@@ -0,0 +1,25 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+func TestCBORTableEncoder(t *testing.T) {
+	testBinaryEncoder(t, encoder.CBOR, (*decoder).cbor)
+
+	buf := &bytes.Buffer{}
+	e := encoder.NewCBORTableEncoder(buf)
+	e.SetHeader([]string{"Offset"})
+	e.Append([]string{"24"})
+	e.Append([]string{""})
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at Render (%v)", err)
+	}
+	expected := []byte{0xA1, 0x66, 'o', 'f', 'f', 's', 'e', 't', 0x62, '2', '4', 0xA1, 0x66, 'o', 'f', 'f', 's', 'e', 't', 0x60}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("expected CBOR: % X, but actual CBOR: % X", expected, buf.Bytes())
+	}
+}
@@ -0,0 +1,239 @@
+package encoder_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+)
+
+// decodedField is a member of a decoded map, which keeps the order of the
+// members.
+type decodedField struct {
+	key   string
+	value interface{}
+}
+
+// decoder is a minimal decoder of the subset of CBOR and MessagePack written
+// by the encoders: integers, strings, byte strings, null, arrays and maps.
+// Integers are int64, byte strings []byte, arrays []interface{} and maps
+// []decodedField.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return make([]byte, n)
+	}
+	if len(d.data) < n {
+		d.err = fmt.Errorf("unexpected end of data")
+		return make([]byte, n)
+	}
+	bs := d.data[:n]
+	d.data = d.data[n:]
+	return bs
+}
+
+func (d *decoder) uint(n int) uint64 {
+	bs := d.next(n)
+	switch n {
+	case 1:
+		return uint64(bs[0])
+	case 2:
+		return uint64(binary.BigEndian.Uint16(bs))
+	case 4:
+		return uint64(binary.BigEndian.Uint32(bs))
+	}
+	return binary.BigEndian.Uint64(bs)
+}
+
+func (d *decoder) cbor() interface{} {
+	initial := d.next(1)[0]
+	major, info := initial>>5, initial&0x1F
+	n := uint64(info)
+	switch info {
+	case 24, 25, 26, 27:
+		n = d.uint(1 << (info - 24))
+	default:
+		if info > 27 {
+			d.err = fmt.Errorf("unsupported additional information %d", info)
+		}
+	}
+	switch major {
+	case 0:
+		return int64(n)
+	case 1:
+		return -1 - int64(n)
+	case 2:
+		return append([]byte{}, d.next(int(n))...)
+	case 3:
+		return string(d.next(int(n)))
+	case 4:
+		return d.array(int(n), d.cbor)
+	case 5:
+		return d.object(int(n), d.cbor)
+	case 7:
+		if info == 22 {
+			return nil
+		}
+	}
+	d.err = fmt.Errorf("unsupported initial byte 0x%02X", initial)
+	return nil
+}
+
+func (d *decoder) messagePack() interface{} {
+	format := d.next(1)[0]
+	switch {
+	case format <= 0x7F:
+		return int64(format)
+	case format >= 0xE0:
+		return int64(int8(format))
+	case format >= 0xA0 && format <= 0xBF:
+		return string(d.next(int(format & 0x1F)))
+	case format >= 0x90 && format <= 0x9F:
+		return d.array(int(format&0x0F), d.messagePack)
+	case format >= 0x80 && format <= 0x8F:
+		return d.object(int(format&0x0F), d.messagePack)
+	}
+	switch format {
+	case 0xC0:
+		return nil
+	case 0xCC, 0xCD, 0xCE, 0xCF:
+		return int64(d.uint(1 << (format - 0xCC)))
+	case 0xD0:
+		return int64(int8(d.uint(1)))
+	case 0xD1:
+		return int64(int16(d.uint(2)))
+	case 0xD2:
+		return int64(int32(d.uint(4)))
+	case 0xD3:
+		return int64(d.uint(8))
+	case 0xD9, 0xDA, 0xDB:
+		return string(d.next(int(d.uint(1 << (format - 0xD9)))))
+	case 0xC4, 0xC5, 0xC6:
+		return append([]byte{}, d.next(int(d.uint(1<<(format-0xC4))))...)
+	case 0xDC, 0xDD:
+		return d.array(int(d.uint(2<<(format-0xDC))), d.messagePack)
+	case 0xDE, 0xDF:
+		return d.object(int(d.uint(2<<(format-0xDE))), d.messagePack)
+	}
+	d.err = fmt.Errorf("unsupported format 0x%02X", format)
+	return nil
+}
+
+func (d *decoder) array(n int, value func() interface{}) []interface{} {
+	values := []interface{}{}
+	for i := 0; i < n && d.err == nil; i++ {
+		values = append(values, value())
+	}
+	return values
+}
+
+func (d *decoder) object(n int, value func() interface{}) []decodedField {
+	fields := []decodedField{}
+	for i := 0; i < n && d.err == nil; i++ {
+		key, ok := value().(string)
+		if !ok {
+			d.err = fmt.Errorf("map key isn't a string")
+		}
+		fields = append(fields, decodedField{key: key, value: value()})
+	}
+	return fields
+}
+
+// decodeAll decodes the data items of data one after another.
+func decodeAll(t *testing.T, data []byte, value func(d *decoder) interface{}) []interface{} {
+	d := &decoder{data: data}
+	var values []interface{}
+	for len(d.data) > 0 && d.err == nil {
+		values = append(values, value(d))
+	}
+	if d.err != nil {
+		t.Fatalf("can't decode %x (%v)", data, d.err)
+	}
+	return values
+}
+
+// testBinaryEncoder round-trips rows and records of f through decode.
+func testBinaryEncoder(t *testing.T, f encoder.FileType, decode func(d *decoder) interface{}) {
+	tests := []struct {
+		header   []string
+		rows     [][]string
+		expected []interface{}
+	}{
+		{
+			[]string{"Character", "Code Point", "Offset"},
+			[][]string{
+				{"a", "U+0061", "23"},
+				{"", "", ""},
+				{"<invalid>"},
+			},
+			[]interface{}{
+				[]decodedField{{"character", "a"}, {"codePoint", "U+0061"}, {"offset", "23"}},
+				[]decodedField{{"character", ""}, {"codePoint", ""}, {"offset", ""}},
+				[]decodedField{{"character", "<invalid>"}, {"codePoint", nil}, {"offset", nil}},
+			},
+		},
+		{
+			nil,
+			[][]string{{"a", "0x61"}, {fmt.Sprintf("%040d", 0)}},
+			[]interface{}{
+				[]interface{}{"a", "0x61"},
+				[]interface{}{fmt.Sprintf("%040d", 0)},
+			},
+		},
+	}
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		e := f.Encoder(buf)
+		if test.header != nil {
+			e.SetHeader(test.header)
+		}
+		for _, row := range test.rows {
+			e.Append(row)
+		}
+		if err := e.Render(); err != nil {
+			t.Fatalf("error occured at Render (%v)", err)
+		}
+		if actual := decodeAll(t, buf.Bytes(), decode); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%v rows are decoded as %#v, but expected value is %#v", f, actual, test.expected)
+		}
+	}
+
+	records := []encoder.CharRecord{
+		{Rune: 'あ', Bytes: []byte{0xE3, 0x81, 0x82}, Offset: 300, Encoding: "UTF-8", Extra: []interface{}{int64(23), nil}},
+		{Rune: 0xFFFD, Bytes: []byte{0xFF}, Offset: 303, Encoding: "UTF-8", Err: unicode.NewInvalidSequenceErr([]byte{0xFF}), Extra: []interface{}{int64(-33), "A"}},
+		{Rune: 0x10FFFF, Bytes: []byte{0xF4, 0x8F, 0xBF, 0xBF}, Offset: 4294967296, Encoding: "UTF-8", Extra: []interface{}{int64(65536), nil}},
+	}
+	expected := []interface{}{
+		[]decodedField{{"character", "あ"}, {"codePoint", int64(0x3042)}, {"name", "HIRAGANA LETTER A"}, {"bytes", []byte{0xE3, 0x81, 0x82}}, {"offset", int64(300)}, {"encoding", "UTF-8"},
+			{"count", int64(23)}, {"hiddenText", nil}},
+		[]decodedField{{"character", nil}, {"codePoint", nil}, {"name", nil}, {"bytes", []byte{0xFF}}, {"offset", int64(303)}, {"encoding", "UTF-8"},
+			{"count", int64(-33)}, {"hiddenText", "A"},
+			{"error", []decodedField{{"kind", "invalid sequence"}, {"message", "invalid sequences: []byte{0xff}"}}}},
+		[]decodedField{{"character", "\U0010FFFF"}, {"codePoint", int64(0x10FFFF)}, {"name", ""}, {"bytes", []byte{0xF4, 0x8F, 0xBF, 0xBF}}, {"offset", int64(4294967296)}, {"encoding", "UTF-8"},
+			{"count", int64(65536)}, {"hiddenText", nil}},
+		// the arrays without header
+		[]interface{}{"あ", int64(0x3042), "HIRAGANA LETTER A", []byte{0xE3, 0x81, 0x82}, int64(300), "UTF-8"},
+	}
+	buf := &bytes.Buffer{}
+	e := f.Encoder(buf)
+	e.SetHeader(append(append([]string{}, encoder.CharHeader...), "Count", "Hidden Text"))
+	for _, r := range records {
+		e.AppendRecord(r)
+	}
+	e = f.Encoder(buf)
+	e.AppendRecord(encoder.CharRecord{Rune: 'あ', Bytes: []byte{0xE3, 0x81, 0x82}, Offset: 300, Encoding: "UTF-8"})
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at Render (%v)", err)
+	}
+	if actual := decodeAll(t, buf.Bytes(), decode); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("%v records are decoded as %#v, but expected value is %#v", f, actual, expected)
+	}
+}
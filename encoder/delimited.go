@@ -0,0 +1,70 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+)
+
+// delimitedEncoder writes CSV and TSV rows as they are appended.
+type delimitedEncoder struct {
+	writer    io.Writer
+	header    []string
+	delimiter rune
+	quoting   Quoting
+	// name is the file type in error messages.
+	name    string
+	started bool
+	err     error
+}
+
+func (de *delimitedEncoder) SetHeader(h []string) {
+	de.header = h
+}
+
+// SetDelimiter changes the delimiter. 0 keeps it.
+func (de *delimitedEncoder) SetDelimiter(d rune) {
+	if d != 0 {
+		de.delimiter = d
+	}
+}
+
+// SetQuoting changes the quoting policy. DefaultQuoting keeps it.
+func (de *delimitedEncoder) SetQuoting(q Quoting) {
+	if q != DefaultQuoting {
+		de.quoting = q
+	}
+}
+
+func (de *delimitedEncoder) writeHeader() {
+	if de.started {
+		return
+	}
+	de.started = true
+	if len(de.header) == 0 {
+		return
+	}
+	if _, err := fmt.Fprintln(de.writer, formatDelimited(de.header, de.delimiter, de.quoting)); err != nil {
+		de.err = fmt.Errorf("can't write %s header (reason; %s)", de.name, err.Error())
+	}
+}
+
+func (de *delimitedEncoder) Append(row []string) {
+	de.writeHeader()
+	if de.err != nil {
+		return
+	}
+	if _, err := fmt.Fprintln(de.writer, formatDelimited(row, de.delimiter, de.quoting)); err != nil {
+		de.err = fmt.Errorf("can't write %s row (reason; %s)", de.name, err.Error())
+	}
+}
+
+func (de *delimitedEncoder) AppendRecord(r CharRecord) {
+	de.Append(r.Row())
+}
+
+// Render writes the header of a table without rows and returns the first
+// error of Append.
+func (de *delimitedEncoder) Render() error {
+	de.writeHeader()
+	return de.err
+}
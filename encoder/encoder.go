@@ -1,31 +1,53 @@
 package encoder
 
 import (
+	"fmt"
 	"io"
 )
 
+// TableEncoder writes a table. SetHeader is called before Append and
+// AppendRecord. The encoders of CSV, TSV, JSON, JSONL, Markdown, AsciiDoc,
+// Stream, Template, CBOR and MessagePack write each row when it is appended,
+// and the others buffer the rows until Render.
 type TableEncoder interface {
 	SetHeader([]string)
 	Append([]string)
+	// AppendRecord appends a character. The text formats write the same row
+	// as Append(r.Row()), and JSON and JSONL keep the types of its fields.
+	AppendRecord(r CharRecord)
 	Render() error
 }
 
+// FileType is a registered Format. The constants are the built-in ones.
 type FileType int
 
 const (
 	None FileType = iota
 	CSV
 	TSV
+	JSON
+	JSONL
+	Markdown
+	AsciiDoc
+	RST
+	HTML
+	XLSX
+	Stream
+	// Template is written by the template of Options.
+	Template
+	CBOR
+	MessagePack
 )
 
-func (f FileType) Encoder(w io.Writer) TableEncoder {
-	switch f {
-	case None:
-		return NewPrettyTableEncoder(w)
-	case CSV:
-		return NewCSVTableEncoder(w)
-	case TSV:
-		return NewTSVTableEncoder(w)
+func (f FileType) String() string {
+	if format := f.Format(); format != nil {
+		return format.Name
 	}
-	return nil
+	return fmt.Sprintf("FileType(%d)", int(f))
+}
+
+// Encoder returns an encoder of f with the default options, or nil if f isn't
+// registered.
+func (f FileType) Encoder(w io.Writer) TableEncoder {
+	return f.EncoderWithOptions(w, Options{})
 }
@@ -20,6 +20,50 @@ func TestFileType_Encoder(t *testing.T) {
 	if _, ok := e.(*encoder.TSVTableEncoder); !ok {
 		t.Errorf("expected Type is *encoder.TSVTableEncoder, but actual type is %v", reflect.TypeOf(e))
 	}
+	e = encoder.JSON.Encoder(nil)
+	if _, ok := e.(*encoder.JSONTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.JSONTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.JSONL.Encoder(nil)
+	if _, ok := e.(*encoder.JSONLTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.JSONLTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.Markdown.Encoder(nil)
+	if _, ok := e.(*encoder.MarkdownTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.MarkdownTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.AsciiDoc.Encoder(nil)
+	if _, ok := e.(*encoder.AsciiDocTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.AsciiDocTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.RST.Encoder(nil)
+	if _, ok := e.(*encoder.RSTTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.RSTTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.HTML.Encoder(nil)
+	if _, ok := e.(*encoder.HTMLTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.HTMLTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.XLSX.Encoder(nil)
+	if _, ok := e.(*encoder.XLSXTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.XLSXTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.Stream.Encoder(nil)
+	if _, ok := e.(*encoder.StreamTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.StreamTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.Template.Encoder(nil)
+	if _, ok := e.(*encoder.TemplateTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.TemplateTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.CBOR.Encoder(nil)
+	if _, ok := e.(*encoder.CBORTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.CBORTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
+	e = encoder.MessagePack.Encoder(nil)
+	if _, ok := e.(*encoder.MessagePackTableEncoder); !ok {
+		t.Errorf("expected Type is *encoder.MessagePackTableEncoder, but actual type is %v", reflect.TypeOf(e))
+	}
 	e = encoder.FileType(-1).Encoder(nil)
 	if e != nil {
 		t.Errorf("expected nil, but actual type is %v", reflect.TypeOf(e))
@@ -0,0 +1,88 @@
+package encoder
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+//go:embed template/report.html
+var reportHTML string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportHTML))
+
+// characterColumns are rendered large.
+var characterColumns = map[string]bool{
+	"Character": true,
+	"Match":     true,
+}
+
+// HTMLTableEncoder writes a standalone HTML page with a sortable and
+// filterable table. The page has no external assets.
+type HTMLTableEncoder struct {
+	writer io.Writer
+	lines  [][]string
+	header []string
+}
+
+func NewHTMLTableEncoder(w io.Writer) *HTMLTableEncoder {
+	return &HTMLTableEncoder{
+		writer: w,
+	}
+}
+
+func (hte *HTMLTableEncoder) SetHeader(h []string) {
+	hte.header = h
+}
+
+func (hte *HTMLTableEncoder) Append(row []string) {
+	hte.lines = append(hte.lines, row)
+}
+
+func (hte *HTMLTableEncoder) AppendRecord(r CharRecord) {
+	hte.Append(r.Row())
+}
+
+type htmlCell struct {
+	Text      string
+	Character bool
+}
+
+type htmlLine struct {
+	Cells []htmlCell
+	// Invalid is true for the invalid sequences of the dump subcommands.
+	Invalid bool
+}
+
+func (hte *HTMLTableEncoder) Render() error {
+	report := struct {
+		Header  []string
+		Lines   []htmlLine
+		Rows    int
+		Invalid int
+	}{
+		Header: hte.header,
+		Rows:   len(hte.lines),
+	}
+	for _, line := range hte.lines {
+		l := htmlLine{}
+		for i, cell := range line {
+			l.Cells = append(l.Cells, htmlCell{
+				Text:      cell,
+				Character: i < len(hte.header) && characterColumns[hte.header[i]],
+			})
+			if cell == "<invalid>" {
+				l.Invalid = true
+			}
+		}
+		if l.Invalid {
+			report.Invalid++
+		}
+		report.Lines = append(report.Lines, l)
+	}
+	if err := reportTemplate.Execute(hte.writer, report); err != nil {
+		return fmt.Errorf("can't write html report (reason; %s)", err.Error())
+	}
+	return nil
+}
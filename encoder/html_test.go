@@ -0,0 +1,37 @@
+package encoder_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+func TestHTMLTableEncoder_Render(t *testing.T) {
+	buf := &bytes.Buffer{}
+	hte := encoder.NewHTMLTableEncoder(buf)
+	hte.SetHeader([]string{"Character", "Code Point", "Name", "Hex"})
+	hte.Append([]string{"<", "U+003C", "LESS-THAN SIGN", "0x3C"})
+	hte.Append([]string{"", "", "<invalid>", "0xFF"})
+	if err := hte.Render(); err != nil {
+		t.Fatalf("error occured at HTMLTableEncoder.Render (%v)", err)
+	}
+
+	html := buf.String()
+	for _, expected := range []string{
+		"<p>2 rows, <strong>1 invalid</strong></p>",
+		"<tr><th>Character</th><th>Code Point</th><th>Name</th><th>Hex</th></tr>",
+		`<tr><td class="character">&lt;</td><td>U&#43;003C</td><td>LESS-THAN SIGN</td><td>0x3C</td></tr>`,
+		`<tr class="invalid"><td class="character"></td><td></td><td>&lt;invalid&gt;</td><td>0xFF</td></tr>`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("HTMLTableEncoder.Render doesn't write %q", expected)
+		}
+	}
+	for _, external := range []string{"src=", "href=", "@import", "url("} {
+		if strings.Contains(html, external) {
+			t.Errorf("HTMLTableEncoder.Render writes an external asset (%q)", external)
+		}
+	}
+}
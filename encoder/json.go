@@ -0,0 +1,229 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/runenames"
+)
+
+// byteString is written as an array of numbers in JSON and as a byte string
+// in the binary formats.
+type byteString []byte
+
+func (bs byteString) MarshalJSON() ([]byte, error) {
+	numbers := make([]int, len(bs))
+	for i, b := range bs {
+		numbers[i] = int(b)
+	}
+	return marshalJSON(numbers)
+}
+
+// jsonKey converts a column name such as "Code Point" to "codePoint".
+func jsonKey(column string) string {
+	var key strings.Builder
+	for i, word := range strings.FieldsFunc(column, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	}) {
+		rs := []rune(strings.ToLower(word))
+		if i > 0 {
+			rs[0] = unicode.ToUpper(rs[0])
+		}
+		key.WriteString(string(rs))
+	}
+	return key.String()
+}
+
+// jsonField is a member of jsonObject.
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+// jsonObject is a JSON object whose members keep their order. The binary
+// formats write it as a map.
+type jsonObject []jsonField
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := marshalJSON(field.key)
+		value, err := marshalJSON(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonRecord is a row as a JSON object of strings whose keys are in the order
+// of the header. A row without header is an array.
+type jsonRecord struct {
+	header []string
+	row    []string
+}
+
+func (r jsonRecord) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r.value())
+}
+
+// value returns the record as a jsonObject, or as a []interface{} without
+// header. The values missing from the row are null.
+func (r jsonRecord) value() interface{} {
+	if len(r.header) == 0 {
+		values := make([]interface{}, len(r.row))
+		for i, value := range r.row {
+			values[i] = value
+		}
+		return values
+	}
+	var object jsonObject
+	for i, column := range r.header {
+		var value interface{}
+		if i < len(r.row) {
+			value = r.row[i]
+		}
+		object = append(object, jsonField{key: jsonKey(column), value: value})
+	}
+	return object
+}
+
+func errorObject(err error) jsonObject {
+	return jsonObject{
+		{key: "kind", value: errorKind(err)},
+		{key: "message", value: err.Error()},
+	}
+}
+
+// charRecordObject is a CharRecord as a JSON object. The keys of Extra are
+// the columns of header after CharHeader. The code point of a grapheme
+// cluster is null, and its code points are the array "codePoints".
+func charRecordObject(r CharRecord, header []string) jsonObject {
+	object := jsonObject{
+		{key: "character", value: nil},
+		{key: "codePoint", value: nil},
+		{key: "name", value: nil},
+		{key: "bytes", value: byteString(r.Bytes)},
+		{key: "offset", value: r.Offset},
+	}
+	switch {
+	case !r.hasRune():
+	case len(r.Runes) == 0:
+		object[0].value = string(r.Rune)
+		object[1].value = r.Rune
+		object[2].value = runenames.Name(r.Rune)
+	default:
+		codePoints := make([]int64, len(r.Runes))
+		names := make([]string, len(r.Runes))
+		for i, c := range r.Runes {
+			codePoints[i] = int64(c)
+			names[i] = runenames.Name(c)
+		}
+		object[0].value = string(r.Runes)
+		object[2].value = strings.Join(names, " + ")
+		object = append(object, jsonField{key: "codePoints", value: codePoints})
+	}
+	if r.Encoding != "" {
+		object = append(object, jsonField{key: "encoding", value: r.Encoding})
+	}
+	for i, value := range r.Extra {
+		key := fmt.Sprintf("extra%d", i+1)
+		if column := len(CharHeader) + i; column < len(header) {
+			key = jsonKey(header[column])
+		}
+		object = append(object, jsonField{key: key, value: value})
+	}
+	if r.Err != nil {
+		object = append(object, jsonField{key: "error", value: errorObject(r.Err)})
+	}
+	return object
+}
+
+// marshalJSON is json.Marshal without escaping of HTML characters, which are
+// common in the names such as "<control>".
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// JSONTableEncoder writes the rows as an array of objects whose keys are the
+// header in lower camel case. The values of Append are strings, and
+// AppendRecord keeps the types of the fields of CharRecord. Each record is
+// written on its own line as soon as it is appended.
+type JSONTableEncoder struct {
+	writer io.Writer
+	header []string
+	count  int
+	err    error
+}
+
+func NewJSONTableEncoder(w io.Writer) *JSONTableEncoder {
+	return &JSONTableEncoder{
+		writer: w,
+	}
+}
+
+func (jte *JSONTableEncoder) SetHeader(h []string) {
+	jte.header = h
+}
+
+func (jte *JSONTableEncoder) write(s string) {
+	if jte.err != nil {
+		return
+	}
+	if _, err := io.WriteString(jte.writer, s); err != nil {
+		jte.err = fmt.Errorf("can't write json (reason; %s)", err.Error())
+	}
+}
+
+func (jte *JSONTableEncoder) Append(row []string) {
+	jte.append(jsonRecord{header: jte.header, row: row})
+}
+
+func (jte *JSONTableEncoder) AppendRecord(r CharRecord) {
+	jte.append(charRecordObject(r, jte.header))
+}
+
+func (jte *JSONTableEncoder) append(record json.Marshaler) {
+	if jte.err != nil {
+		return
+	}
+	bs, err := record.MarshalJSON()
+	if err != nil {
+		jte.err = fmt.Errorf("can't write json (reason; %s)", err.Error())
+		return
+	}
+	if jte.count == 0 {
+		jte.write("[\n  ")
+	} else {
+		jte.write(",\n  ")
+	}
+	jte.write(string(bs))
+	jte.count++
+}
+
+// Render closes the array and returns the first error of Append.
+func (jte *JSONTableEncoder) Render() error {
+	if jte.count == 0 {
+		jte.write("[")
+	}
+	jte.write("\n]\n")
+	return jte.err
+}
@@ -0,0 +1,82 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+)
+
+func TestJSONTableEncoder_Render(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jte := encoder.NewJSONTableEncoder(buf)
+	jte.SetHeader(append(append([]string{}, encoder.CharHeader...), "Count", "Hidden Text"))
+	jte.AppendRecord(encoder.CharRecord{Rune: '<', Bytes: []byte{0x3C}, Extra: []interface{}{int64(2), nil}})
+	jte.AppendRecord(encoder.CharRecord{Rune: 0xFFFD, Bytes: []byte{0xFF}, Offset: 1, Err: unicode.NewInvalidSequenceErr([]byte{0xFF}), Extra: []interface{}{int64(1), `"A"`}})
+	if err := jte.Render(); err != nil {
+		t.Fatalf("error occured at JSONTableEncoder.Render (%v)", err)
+	}
+
+	expected := `[
+  {"character":"<","codePoint":60,"name":"LESS-THAN SIGN","bytes":[60],"offset":0,"count":2,"hiddenText":null},
+  {"character":null,"codePoint":null,"name":null,"bytes":[255],"offset":1,"count":1,"hiddenText":"\"A\"","error":{"kind":"invalid sequence","message":"invalid sequences: []byte{0xff}"}}
+]
+`
+	if buf.String() != expected {
+		t.Errorf("expected JSON: %q, but JSONTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
+
+func TestJSONTableEncoder_RenderWithoutHeader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jte := encoder.NewJSONTableEncoder(buf)
+	jte.Append([]string{"File", "U+0041 U+0042"})
+	if err := jte.Render(); err != nil {
+		t.Fatalf("error occured at JSONTableEncoder.Render (%v)", err)
+	}
+
+	expected := "[\n  [\"File\",\"U+0041 U+0042\"]\n]\n"
+	if buf.String() != expected {
+		t.Errorf("expected JSON: %q, but JSONTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
+
+func TestJSONTableEncoder_RenderStrings(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jte := encoder.NewJSONTableEncoder(buf)
+	jte.SetHeader([]string{"Line", "Code Point", "Hex"})
+	jte.Append([]string{"1", "U+0041", "0x41"})
+	jte.Append([]string{"<invalid>"})
+	if err := jte.Render(); err != nil {
+		t.Fatalf("error occured at JSONTableEncoder.Render (%v)", err)
+	}
+
+	// the values of the rows are strings, and the missing ones are null
+	expected := `[
+  {"line":"1","codePoint":"U+0041","hex":"0x41"},
+  {"line":"<invalid>","codePoint":null,"hex":null}
+]
+`
+	if buf.String() != expected {
+		t.Errorf("expected JSON: %q, but JSONTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
+
+func TestJSONTableEncoder_RenderError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jte := encoder.NewJSONTableEncoder(buf)
+	jte.AppendRecord(encoder.CharRecord{Rune: 'a', Bytes: []byte{0x61}})
+	jte.AppendRecord(encoder.CharRecord{Rune: 'b', Bytes: []byte{0x62}, Extra: []interface{}{make(chan int)}})
+	jte.AppendRecord(encoder.CharRecord{Rune: 'c', Bytes: []byte{0x63}})
+	if err := jte.Render(); err == nil {
+		t.Errorf("JSONTableEncoder.Render doesn't return the error of MarshalJSON")
+	}
+
+	// nothing is written after the record which can't be marshaled
+	expected := `[
+  {"character":"a","codePoint":97,"name":"LATIN SMALL LETTER A","bytes":[97],"offset":0}`
+	if buf.String() != expected {
+		t.Errorf("expected JSON: %q, but JSONTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,51 @@
+package encoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONLTableEncoder writes each row as a JSON Lines record as soon as it is
+// appended. The records are the same as the ones of JSONTableEncoder.
+type JSONLTableEncoder struct {
+	writer io.Writer
+	header []string
+	err    error
+}
+
+func NewJSONLTableEncoder(w io.Writer) *JSONLTableEncoder {
+	return &JSONLTableEncoder{
+		writer: w,
+	}
+}
+
+func (jte *JSONLTableEncoder) SetHeader(h []string) {
+	jte.header = h
+}
+
+func (jte *JSONLTableEncoder) Append(row []string) {
+	jte.append(jsonRecord{header: jte.header, row: row})
+}
+
+func (jte *JSONLTableEncoder) AppendRecord(r CharRecord) {
+	jte.append(charRecordObject(r, jte.header))
+}
+
+func (jte *JSONLTableEncoder) append(record json.Marshaler) {
+	if jte.err != nil {
+		return
+	}
+	bs, err := record.MarshalJSON()
+	if err == nil {
+		_, err = jte.writer.Write(append(bs, '\n'))
+	}
+	if err != nil {
+		jte.err = fmt.Errorf("can't write json lines record (reason; %s)", err.Error())
+	}
+}
+
+// Render returns the first error of Append.
+func (jte *JSONLTableEncoder) Render() error {
+	return jte.err
+}
@@ -0,0 +1,26 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+func TestJSONLTableEncoder_Append(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jte := encoder.NewJSONLTableEncoder(buf)
+	jte.SetHeader([]string{"File", "Line", "Whole-Script Confusable", "Code Points"})
+	jte.Append([]string{"a.txt", "1", "yes", "U+3042 U+3044"})
+	// records are written before Render
+	expected := `{"file":"a.txt","line":"1","wholeScriptConfusable":"yes","codePoints":"U+3042 U+3044"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected JSON Lines: %q, but JSONLTableEncoder.Append writes: %q", expected, buf.String())
+	}
+	if err := jte.Render(); err != nil {
+		t.Errorf("error occured at JSONLTableEncoder.Render (%v)", err)
+	}
+	if buf.String() != expected {
+		t.Errorf("JSONLTableEncoder.Render writes %q", buf.String()[len(expected):])
+	}
+}
@@ -0,0 +1,113 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MarkdownTableEncoder writes a GitHub Flavored Markdown table. Each row is
+// written as soon as it is appended.
+type MarkdownTableEncoder struct {
+	writer  io.Writer
+	header  []string
+	started bool
+	err     error
+}
+
+func NewMarkdownTableEncoder(w io.Writer) *MarkdownTableEncoder {
+	return &MarkdownTableEncoder{
+		writer: w,
+	}
+}
+
+func (mte *MarkdownTableEncoder) SetHeader(h []string) {
+	mte.header = h
+}
+
+var markdownReplacer = newEscapeReplacer("\\`*_[]<>|~&#!", func(c rune) string {
+	return `\` + string(c)
+})
+
+func (mte *MarkdownTableEncoder) writeRow(row []string, escape bool) {
+	if mte.err != nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString("|")
+	for _, cell := range row {
+		if escape {
+			cell = markdownReplacer.Replace(singleLine(cell))
+		}
+		b.WriteString(" " + cell + " |")
+	}
+	b.WriteString("\n")
+	if _, err := io.WriteString(mte.writer, b.String()); err != nil {
+		mte.err = fmt.Errorf("can't write markdown table (reason; %s)", err.Error())
+	}
+}
+
+// writeHeader writes the header with the number of columns of the first row.
+// A table without header gets an empty one because Markdown tables require
+// it.
+func (mte *MarkdownTableEncoder) writeHeader(columns int) {
+	if mte.started {
+		return
+	}
+	mte.started = true
+	header := mte.header
+	if len(header) == 0 {
+		header = make([]string, columns)
+	}
+	if len(header) == 0 {
+		return
+	}
+	delimiters := make([]string, len(header))
+	for i := range delimiters {
+		delimiters[i] = "---"
+	}
+	mte.writeRow(header, true)
+	mte.writeRow(delimiters, false)
+}
+
+func (mte *MarkdownTableEncoder) Append(row []string) {
+	mte.writeHeader(len(row))
+	mte.writeRow(row, true)
+}
+
+func (mte *MarkdownTableEncoder) AppendRecord(r CharRecord) {
+	mte.Append(r.Row())
+}
+
+// Render writes the header of a table without rows and returns the first
+// error of Append.
+func (mte *MarkdownTableEncoder) Render() error {
+	mte.writeHeader(0)
+	return mte.err
+}
+
+// newEscapeReplacer returns a replacer which replaces each of chars with the
+// result of escape.
+func newEscapeReplacer(chars string, escape func(c rune) string) *strings.Replacer {
+	var oldnew []string
+	for _, c := range chars {
+		oldnew = append(oldnew, string(c), escape(c))
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// singleLine replaces line breaks, which can't be in a table cell, with
+// spaces.
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(s)
+}
+
+func columns(lines [][]string) int {
+	n := 0
+	for _, line := range lines {
+		if len(line) > n {
+			n = len(line)
+		}
+	}
+	return n
+}
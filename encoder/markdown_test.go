@@ -0,0 +1,43 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+func TestMarkdownTableEncoder_Render(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mte := encoder.NewMarkdownTableEncoder(buf)
+	mte.SetHeader([]string{"Character", "Name"})
+	mte.Append([]string{"|", "VERTICAL LINE"})
+	mte.Append([]string{"`", "GRAVE ACCENT"})
+	mte.Append([]string{`\n`, "<control>"})
+	if err := mte.Render(); err != nil {
+		t.Fatalf("error occured at MarkdownTableEncoder.Render (%v)", err)
+	}
+
+	expected := "| Character | Name |\n" +
+		"| --- | --- |\n" +
+		"| \\| | VERTICAL LINE |\n" +
+		"| \\` | GRAVE ACCENT |\n" +
+		"| \\\\n | \\<control\\> |\n"
+	if buf.String() != expected {
+		t.Errorf("expected Markdown table: %q, but MarkdownTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
+
+func TestMarkdownTableEncoder_RenderWithoutHeader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mte := encoder.NewMarkdownTableEncoder(buf)
+	mte.Append([]string{"a", "b"})
+	if err := mte.Render(); err != nil {
+		t.Fatalf("error occured at MarkdownTableEncoder.Render (%v)", err)
+	}
+
+	expected := "|  |  |\n| --- | --- |\n| a | b |\n"
+	if buf.String() != expected {
+		t.Errorf("expected Markdown table: %q, but MarkdownTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
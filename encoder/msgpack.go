@@ -0,0 +1,119 @@
+package encoder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// MessagePackTableEncoder writes the rows as a stream of MessagePack
+// objects, one for each row. Code points and numbers are integers and bytes
+// are bin objects.
+type MessagePackTableEncoder struct {
+	binaryEncoder
+}
+
+func NewMessagePackTableEncoder(w io.Writer) *MessagePackTableEncoder {
+	return &MessagePackTableEncoder{
+		binaryEncoder{writer: w, name: "messagepack", append: appendMessagePack},
+	}
+}
+
+// appendMessagePackHead appends the header of a str, bin, array or map of
+// length n. fix is the prefix of the fix format, which is used when n is less
+// than fixLimit, and formats are the 8 (0 for none), 16 and 32 bit ones.
+func appendMessagePackHead(buf []byte, fix byte, fixLimit int, formats [3]byte, n int) []byte {
+	switch {
+	case n < fixLimit:
+		return append(buf, fix|byte(n))
+	case n <= 0xFF && formats[0] != 0:
+		return append(buf, formats[0], byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, formats[1], 0, 0)
+		binary.BigEndian.PutUint16(buf[len(buf)-2:], uint16(n))
+	default:
+		buf = append(buf, formats[2], 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(buf[len(buf)-4:], uint32(n))
+	}
+	return buf
+}
+
+func appendMessagePackString(buf []byte, s string) []byte {
+	buf = appendMessagePackHead(buf, 0xA0, 32, [3]byte{0xD9, 0xDA, 0xDB}, len(s))
+	return append(buf, s...)
+}
+
+// appendMessagePackInt appends n in the shortest format.
+func appendMessagePackInt(buf []byte, n int64) []byte {
+	switch {
+	case n >= 0 && n <= 0x7F, n < 0 && n >= -32:
+		return append(buf, byte(n))
+	case n >= 0 && n <= 0xFF:
+		return append(buf, 0xCC, byte(n))
+	case n >= 0 && n <= 0xFFFF:
+		buf = append(buf, 0xCD, 0, 0)
+		binary.BigEndian.PutUint16(buf[len(buf)-2:], uint16(n))
+	case n >= 0 && n <= 0xFFFFFFFF:
+		buf = append(buf, 0xCE, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(buf[len(buf)-4:], uint32(n))
+	case n >= 0:
+		buf = append(buf, 0xCF, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(buf[len(buf)-8:], uint64(n))
+	case n >= -0x80:
+		return append(buf, 0xD0, byte(n))
+	case n >= -0x8000:
+		buf = append(buf, 0xD1, 0, 0)
+		binary.BigEndian.PutUint16(buf[len(buf)-2:], uint16(n))
+	case n >= -0x80000000:
+		buf = append(buf, 0xD2, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(buf[len(buf)-4:], uint32(n))
+	default:
+		buf = append(buf, 0xD3, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(buf[len(buf)-8:], uint64(n))
+	}
+	return buf
+}
+
+// appendMessagePack appends v, which is a value of jsonRecord or
+// charRecordObject.
+func appendMessagePack(buf []byte, v interface{}) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		buf = append(buf, 0xC0)
+	case string:
+		buf = appendMessagePackString(buf, v)
+	case byteString:
+		buf = appendMessagePackHead(buf, 0, 0, [3]byte{0xC4, 0xC5, 0xC6}, len(v))
+		buf = append(buf, v...)
+	case int:
+		buf = appendMessagePackInt(buf, int64(v))
+	case int32:
+		buf = appendMessagePackInt(buf, int64(v))
+	case int64:
+		buf = appendMessagePackInt(buf, v)
+	case []int64:
+		buf = appendMessagePackHead(buf, 0x90, 16, [3]byte{0, 0xDC, 0xDD}, len(v))
+		for _, n := range v {
+			buf = appendMessagePackInt(buf, n)
+		}
+	case []interface{}:
+		buf = appendMessagePackHead(buf, 0x90, 16, [3]byte{0, 0xDC, 0xDD}, len(v))
+		for _, value := range v {
+			if buf, err = appendMessagePack(buf, value); err != nil {
+				return nil, err
+			}
+		}
+	case jsonObject:
+		buf = appendMessagePackHead(buf, 0x80, 16, [3]byte{0, 0xDE, 0xDF}, len(v))
+		for _, field := range v {
+			buf = appendMessagePackString(buf, field.key)
+			if buf, err = appendMessagePack(buf, field.value); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+	return buf, nil
+}
@@ -0,0 +1,25 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+func TestMessagePackTableEncoder(t *testing.T) {
+	testBinaryEncoder(t, encoder.MessagePack, (*decoder).messagePack)
+
+	buf := &bytes.Buffer{}
+	e := encoder.NewMessagePackTableEncoder(buf)
+	e.SetHeader([]string{"Offset"})
+	e.Append([]string{"24"})
+	e.Append([]string{""})
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at Render (%v)", err)
+	}
+	expected := []byte{0x81, 0xA6, 'o', 'f', 'f', 's', 'e', 't', 0xA2, '2', '4', 0x81, 0xA6, 'o', 'f', 'f', 's', 'e', 't', 0xA0}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("expected MessagePack: % X, but actual MessagePack: % X", expected, buf.Bytes())
+	}
+}
@@ -0,0 +1,188 @@
+package encoder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/moba1/usd/charset"
+)
+
+// Quoting is the policy of quoting the fields of CSV and TSV.
+type Quoting int
+
+const (
+	// DefaultQuoting is QuoteMinimal for CSV and QuoteNone for TSV.
+	DefaultQuoting Quoting = iota
+	// QuoteMinimal quotes the fields which contain the delimiter, quotes or
+	// line breaks, or begin with a space.
+	QuoteMinimal
+	QuoteAll
+	QuoteNone
+)
+
+// Options change how the text encoders write. The zero value writes UTF-8
+// without byte order mark and with LF line endings.
+type Options struct {
+	// Charset is the character encoding of the output. nil is UTF-8.
+	// Characters which it can't encode are written as "?".
+	Charset *charset.Charset
+	// BOM writes the byte order mark of Charset first.
+	BOM  bool
+	CRLF bool
+	// Delimiter and Quoting are used by CSV and TSV. The zero values are the
+	// defaults of the file type.
+	Delimiter rune
+	Quoting   Quoting
+	// Template is the template of the Template file type.
+	Template *template.Template
+}
+
+// EncoderWithOptions is Encoder with Options. The Charset, BOM and CRLF
+// options apply to every text format and Delimiter and Quoting to CSV and
+// TSV.
+func (f FileType) EncoderWithOptions(w io.Writer, o Options) TableEncoder {
+	format := f.Format()
+	if format == nil {
+		return nil
+	}
+	if format.Binary || (o.Charset == nil && !o.BOM && !o.CRLF) {
+		return format.New(w, o)
+	}
+	tw := NewTextWriter(w, o)
+	return &textEncoder{TableEncoder: format.New(tw, o), writer: tw}
+}
+
+// textEncoder flushes its TextWriter after rendering.
+type textEncoder struct {
+	TableEncoder
+	writer *TextWriter
+}
+
+func (te *textEncoder) Render() error {
+	err := te.TableEncoder.Render()
+	if flushErr := te.writer.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+// TextWriter converts the UTF-8 text written by the encoders to the charset
+// and line endings of the options. Flush must be called after the text is
+// written.
+type TextWriter struct {
+	writer  io.Writer
+	options Options
+	started bool
+	// pending is an incomplete UTF-8 sequence at the end of the last Write
+	pending []byte
+}
+
+// NewTextWriter returns a TextWriter which writes to w with the Charset, BOM
+// and CRLF options of o.
+func NewTextWriter(w io.Writer, o Options) *TextWriter {
+	return &TextWriter{writer: w, options: o}
+}
+
+func (tw *TextWriter) start() error {
+	if tw.started {
+		return nil
+	}
+	tw.started = true
+	if !tw.options.BOM {
+		return nil
+	}
+	bom := []byte{0xEF, 0xBB, 0xBF}
+	if tw.options.Charset != nil {
+		bom = tw.options.Charset.BOM()
+	}
+	if len(bom) == 0 {
+		return fmt.Errorf("%s has no byte order mark", tw.options.Charset.Name())
+	}
+	_, err := tw.writer.Write(bom)
+	return err
+}
+
+func (tw *TextWriter) Write(p []byte) (int, error) {
+	if err := tw.start(); err != nil {
+		return 0, err
+	}
+	text := append(tw.pending, p...)
+	tw.pending = nil
+	var out bytes.Buffer
+	for len(text) > 0 {
+		c, size := utf8.DecodeRune(text)
+		if c == utf8.RuneError && size < utf8.UTFMax && !utf8.FullRune(text) {
+			tw.pending = append([]byte{}, text...)
+			break
+		}
+		text = text[size:]
+		if c == '\n' && tw.options.CRLF {
+			tw.encode(&out, '\r')
+		}
+		tw.encode(&out, c)
+	}
+	if _, err := tw.writer.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (tw *TextWriter) encode(out *bytes.Buffer, c rune) {
+	if tw.options.Charset == nil {
+		out.WriteRune(c)
+		return
+	}
+	bs, err := tw.options.Charset.Encode(c)
+	var unencodable *charset.UnencodableErr
+	if errors.As(err, &unencodable) {
+		bs, _ = tw.options.Charset.Encode('?')
+	}
+	out.Write(bs)
+}
+
+// Flush writes the byte order mark of an empty output and an incomplete
+// sequence left by Write.
+func (tw *TextWriter) Flush() error {
+	if err := tw.start(); err != nil {
+		return err
+	}
+	if len(tw.pending) == 0 {
+		return nil
+	}
+	var out bytes.Buffer
+	tw.encode(&out, utf8.RuneError)
+	tw.pending = nil
+	_, err := tw.writer.Write(out.Bytes())
+	return err
+}
+
+// formatDelimited formats a row of CSV or TSV without line ending. Quotes are
+// escaped by doubling them as RFC 4180 describes.
+func formatDelimited(row []string, delimiter rune, quoting Quoting) string {
+	fields := make([]string, len(row))
+	for i, field := range row {
+		if quoting == QuoteAll || (quoting == QuoteMinimal && fieldNeedsQuotes(field, delimiter)) {
+			field = `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+		}
+		fields[i] = field
+	}
+	return strings.Join(fields, string(delimiter))
+}
+
+// fieldNeedsQuotes is the rule of encoding/csv.
+func fieldNeedsQuotes(field string, delimiter rune) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || strings.ContainsRune(field, delimiter) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+	c, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(c)
+}
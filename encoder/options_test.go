@@ -0,0 +1,105 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+)
+
+func render(t *testing.T, f encoder.FileType, o encoder.Options, rows ...[]string) []byte {
+	buf := &bytes.Buffer{}
+	e := f.EncoderWithOptions(buf, o)
+	e.SetHeader(rows[0])
+	for _, row := range rows[1:] {
+		e.Append(row)
+	}
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at Render (%v)", err)
+	}
+	return buf.Bytes()
+}
+
+func lookup(t *testing.T, name string, endian unicode.Endian) *charset.Charset {
+	cs, err := charset.Lookup(name, endian)
+	if err != nil {
+		t.Fatalf("charset.Lookup(%q) returns error: %v", name, err)
+	}
+	return cs
+}
+
+func TestFileType_EncoderWithOptions(t *testing.T) {
+	header := []string{"Character", "Name"}
+	row := []string{`"`, "QUOTATION MARK, “SMART”"}
+	tests := []struct {
+		name     string
+		fileType encoder.FileType
+		options  encoder.Options
+		expected []byte
+	}{
+		{"default csv", encoder.CSV, encoder.Options{},
+			[]byte("Character,Name\n\"\"\"\",\"QUOTATION MARK, “SMART”\"\n")},
+		{"default tsv", encoder.TSV, encoder.Options{},
+			[]byte("Character\tName\n\"\tQUOTATION MARK, “SMART”\n")},
+		{"utf-8 with bom and crlf", encoder.CSV, encoder.Options{BOM: true, CRLF: true, Quoting: encoder.QuoteAll, Delimiter: ';'},
+			[]byte("\xEF\xBB\xBF\"Character\";\"Name\"\r\n\"\"\"\";\"QUOTATION MARK, “SMART”\"\r\n")},
+		{"tsv quoted", encoder.TSV, encoder.Options{Quoting: encoder.QuoteMinimal},
+			[]byte("Character\tName\n\"\"\"\"\tQUOTATION MARK, “SMART”\n")},
+		{"csv unquoted", encoder.CSV, encoder.Options{Quoting: encoder.QuoteNone},
+			[]byte("Character,Name\n\",QUOTATION MARK, “SMART”\n")},
+		{"shift_jis", encoder.TSV, encoder.Options{Charset: lookup(t, "ShiftJIS", unicode.BigEndian)},
+			[]byte("Character\tName\n\"\tQUOTATION MARK, \x81gSMART\x81h\n")},
+	}
+	for _, test := range tests {
+		actual := render(t, test.fileType, test.options, header, row)
+		if !bytes.Equal(actual, test.expected) {
+			t.Errorf("%s: expected %q, but Render writes %q", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestFileType_EncoderWithOptionsUTF16(t *testing.T) {
+	o := encoder.Options{Charset: lookup(t, "UTF16", unicode.LittleEndian), BOM: true, CRLF: true}
+	actual := render(t, encoder.TSV, o, []string{"\U0001F600"})
+	expected := []byte{0xFF, 0xFE, 0x3D, 0xD8, 0x00, 0xDE, '\r', 0x00, '\n', 0x00}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("expected % X, but Render writes % X", expected, actual)
+	}
+}
+
+func TestFileType_EncoderWithOptionsUnencodable(t *testing.T) {
+	o := encoder.Options{Charset: lookup(t, "ShiftJIS", unicode.BigEndian)}
+	actual := render(t, encoder.TSV, o, []string{"a\U0001F600b"})
+	if string(actual) != "a?b\n" {
+		t.Errorf("expected %q, but Render writes %q", "a?b\n", actual)
+	}
+}
+
+func TestFileType_EncoderWithOptionsNoBOM(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := encoder.CSV.EncoderWithOptions(buf, encoder.Options{Charset: lookup(t, "ShiftJIS", unicode.BigEndian), BOM: true})
+	e.Append([]string{"a"})
+	if err := e.Render(); err == nil {
+		t.Errorf("Render with the byte order mark of ShiftJIS doesn't return error")
+	}
+}
+
+func TestTextWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := encoder.NewTextWriter(buf, encoder.Options{Charset: lookup(t, "UTF16", unicode.LittleEndian), BOM: true, CRLF: true})
+	// the sequence of U+3042 is split between the writes
+	for _, s := range []string{"a\xE3\x81", "\x82\n"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatalf("error occured at TextWriter.Write (%v)", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("error occured at TextWriter.Flush (%v)", err)
+	}
+	expected := []byte{0xFF, 0xFE, 'a', 0x00, 0x42, 0x30, '\r', 0x00, '\n', 0x00}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("expected % X, but TextWriter writes % X", expected, buf.Bytes())
+	}
+}
@@ -25,6 +25,10 @@ func (pte *PrettyTableEncoder) Append(row []string) {
 	pte.tableWriter.Append(row)
 }
 
+func (pte *PrettyTableEncoder) AppendRecord(r CharRecord) {
+	pte.Append(r.Row())
+}
+
 func (pte *PrettyTableEncoder) Render() error {
 	pte.tableWriter.Render()
 	return nil
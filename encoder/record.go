@@ -0,0 +1,122 @@
+package encoder
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/unicode"
+	"golang.org/x/text/unicode/runenames"
+)
+
+// CharHeader is the header of the columns of CharRecord.Row.
+var CharHeader = []string{"Character", "Code Point", "Name", "Hex"}
+
+// CharRecord is a character read by a dump subcommand. Encoders render it
+// with AppendRecord, where the text formats write Row and the structured
+// formats keep the types.
+type CharRecord struct {
+	Rune rune
+	// Runes are the code points of a grapheme cluster of more than one code
+	// point. The record is the cluster instead of Rune then.
+	Runes []rune
+	Bytes []byte
+	// Offset is the byte offset from the beginning of the input.
+	Offset int64
+	// Encoding is the name of the character encoding such as "UTF-16LE".
+	Encoding string
+	// Err is the decoding error of Bytes, where Rune is meaningless, or
+	// charset.UnencodableErr of Rune, where Bytes is empty.
+	Err error
+	// Extra are the values of the columns after CharHeader, such as
+	// "Hidden Text". The text formats write them with fmt.Sprint and nil as
+	// an empty string.
+	Extra []interface{}
+}
+
+// Row returns the record as the columns of CharHeader followed by Extra.
+func (r CharRecord) Row() []string {
+	var row []string
+	if !r.hasRune() {
+		row = []string{"", "", "<invalid>", HexString(r.Bytes)}
+	} else {
+		var characters strings.Builder
+		codePoints := make([]string, len(r.runes()))
+		names := make([]string, len(r.runes()))
+		for i, c := range r.runes() {
+			characters.WriteString(GraphicString(c))
+			codePoints[i] = fmt.Sprintf("%U", c)
+			names[i] = runenames.Name(c)
+		}
+		row = []string{
+			characters.String(),
+			strings.Join(codePoints, " "),
+			strings.Join(names, " + "),
+			HexString(r.Bytes),
+		}
+	}
+	for _, value := range r.Extra {
+		if value == nil {
+			row = append(row, "")
+		} else {
+			row = append(row, fmt.Sprint(value))
+		}
+	}
+	return row
+}
+
+// runes returns Runes, or Rune if the record is a single code point.
+func (r CharRecord) runes() []rune {
+	if len(r.Runes) > 0 {
+		return r.Runes
+	}
+	return []rune{r.Rune}
+}
+
+// hasRune reports whether Rune is meaningful, which it is unless Bytes failed
+// to decode.
+func (r CharRecord) hasRune() bool {
+	var unencodable *charset.UnencodableErr
+	return r.Err == nil || errors.As(r.Err, &unencodable)
+}
+
+// errorKind names the decoding errors of the unicode package and the encoding
+// error of the charset package.
+func errorKind(err error) string {
+	var (
+		invalidSequence  *unicode.InvalidSequenceErr
+		unexpectedEOF    *unicode.UnexpectedEofErr
+		invalidCodePoint *unicode.InvalidCodePointErr
+		unencodable      *charset.UnencodableErr
+	)
+	switch {
+	case errors.As(err, &invalidSequence):
+		return "invalid sequence"
+	case errors.As(err, &unexpectedEOF):
+		return "unexpected end of input"
+	case errors.As(err, &invalidCodePoint):
+		return "invalid code point"
+	case errors.As(err, &unencodable):
+		return "unencodable character"
+	}
+	return "error"
+}
+
+// GraphicString returns c, or its Go escape sequence if c isn't graphic.
+func GraphicString(c rune) string {
+	if c == '\'' {
+		return "'"
+	}
+	return strings.Trim(strconv.QuoteRuneToGraphic(c), "'")
+}
+
+// HexString formats bs as "0xE3 0x81 0x82".
+func HexString(bs []byte) string {
+	hexes := []string{}
+	for _, b := range bs {
+		hexes = append(hexes, fmt.Sprintf("0x%02X", b))
+	}
+	return strings.Join(hexes, " ")
+}
@@ -0,0 +1,90 @@
+package encoder_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+)
+
+// unencodableErr returns the error of encoding c in Latin-1.
+func unencodableErr(t *testing.T, c rune) error {
+	cs, err := charset.Lookup("Latin1", unicode.BigEndian)
+	if err != nil {
+		t.Fatalf("charset.Lookup returns error: %v", err)
+	}
+	_, err = cs.Encode(c)
+	if err == nil {
+		t.Fatalf("Charset.Encode(%U) doesn't return error", c)
+	}
+	return err
+}
+
+func TestCharRecord_Row(t *testing.T) {
+	tests := []struct {
+		record   encoder.CharRecord
+		expected []string
+	}{
+		{encoder.CharRecord{Rune: 'a', Bytes: []byte{0x61}}, []string{"a", "U+0061", "LATIN SMALL LETTER A", "0x61"}},
+		{encoder.CharRecord{Rune: '\n', Bytes: []byte{0x0A}, Extra: []interface{}{`"A"`}}, []string{`\n`, "U+000A", "<control>", "0x0A", `"A"`}},
+		{encoder.CharRecord{Runes: []rune{'e', 0x301}, Bytes: []byte{0x65, 0xCC, 0x81}, Extra: []interface{}{int64(2), nil}}, []string{"e\u0301", "U+0065 U+0301", "LATIN SMALL LETTER E + COMBINING ACUTE ACCENT", "0x65 0xCC 0x81", "2", ""}},
+		{encoder.CharRecord{Rune: 0xFFFD, Bytes: []byte{0xFF}, Err: unicode.NewInvalidSequenceErr([]byte{0xFF})}, []string{"", "", "<invalid>", "0xFF"}},
+		{encoder.CharRecord{Rune: 'あ', Err: unencodableErr(t, 'あ')}, []string{"あ", "U+3042", "HIRAGANA LETTER A", ""}},
+	}
+	for _, test := range tests {
+		if actual := test.record.Row(); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("CharRecord.Row returns %q, but expected value is %q", actual, test.expected)
+		}
+	}
+}
+
+func TestTableEncoder_AppendRecord(t *testing.T) {
+	records := []encoder.CharRecord{
+		{Rune: 'あ', Bytes: []byte{0xE3, 0x81, 0x82}, Offset: 3, Encoding: "UTF-8", Extra: []interface{}{""}},
+		{Rune: 0xFFFD, Bytes: []byte{0xFF}, Offset: 6, Encoding: "UTF-8", Err: unicode.NewInvalidSequenceErr([]byte{0xFF}), Extra: []interface{}{`"A"`}},
+		{Rune: 'あ', Offset: 7, Encoding: "Latin1", Err: unencodableErr(t, 'あ'), Extra: []interface{}{""}},
+		{Runes: []rune{'e', 0x301}, Bytes: []byte{0x65, 0xCC, 0x81}, Offset: 9, Encoding: "UTF-8", Extra: []interface{}{nil}},
+	}
+	header := append(append([]string{}, encoder.CharHeader...), "Hidden Text")
+
+	buf := &bytes.Buffer{}
+	e := encoder.JSONL.Encoder(buf)
+	e.SetHeader(header)
+	for _, r := range records {
+		e.AppendRecord(r)
+	}
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at Render (%v)", err)
+	}
+	expected := `{"character":"あ","codePoint":12354,"name":"HIRAGANA LETTER A","bytes":[227,129,130],"offset":3,"encoding":"UTF-8","hiddenText":""}` + "\n" +
+		`{"character":null,"codePoint":null,"name":null,"bytes":[255],"offset":6,"encoding":"UTF-8","hiddenText":"\"A\"","error":{"kind":"invalid sequence","message":"invalid sequences: []byte{0xff}"}}` + "\n" +
+		`{"character":"あ","codePoint":12354,"name":"HIRAGANA LETTER A","bytes":[],"offset":7,"encoding":"Latin1","hiddenText":"","error":{"kind":"unencodable character","message":"U+3042 can't be encoded in Latin1"}}` + "\n" +
+		`{"character":"` + "e\u0301" + `","codePoint":null,"name":"LATIN SMALL LETTER E + COMBINING ACUTE ACCENT","bytes":[101,204,129],"offset":9,"codePoints":[101,769],"encoding":"UTF-8","hiddenText":null}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected JSON Lines: %q, but AppendRecord writes: %q", expected, buf.String())
+	}
+
+	// the text formats write the rows
+	for _, f := range []encoder.FileType{encoder.CSV, encoder.TSV, encoder.Markdown, encoder.Stream} {
+		byRecord, byRow := &bytes.Buffer{}, &bytes.Buffer{}
+		e, f2 := f.Encoder(byRecord), f.Encoder(byRow)
+		e.SetHeader(header)
+		f2.SetHeader(header)
+		for _, r := range records {
+			e.AppendRecord(r)
+			f2.Append(r.Row())
+		}
+		if err := e.Render(); err != nil {
+			t.Fatalf("error occured at Render (%v)", err)
+		}
+		if err := f2.Render(); err != nil {
+			t.Fatalf("error occured at Render (%v)", err)
+		}
+		if byRecord.String() != byRow.String() {
+			t.Errorf("the encoder of file type %d writes %q for the records, but %q for the rows", f, byRecord.String(), byRow.String())
+		}
+	}
+}
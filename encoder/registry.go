@@ -0,0 +1,145 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Format describes an output format in the registry.
+type Format struct {
+	// Name and Aliases are matched ignoring case.
+	Name        string
+	Aliases     []string
+	Description string
+	// Extensions are the file extensions such as ".csv" which select the
+	// format.
+	Extensions []string
+	// Binary formats ignore the Charset, BOM and CRLF options.
+	Binary bool
+	// New returns an encoder which writes to w. The Charset, BOM and CRLF
+	// options of a text format are already applied to w.
+	New func(w io.Writer, o Options) TableEncoder
+}
+
+var (
+	registryMutex sync.RWMutex
+	// formats are indexed by FileType.
+	formats = []Format{
+		None: {Name: "None", Aliases: []string{"Pretty"}, Description: "table with borders", Extensions: []string{".txt"},
+			New: func(w io.Writer, o Options) TableEncoder { return NewPrettyTableEncoder(w) }},
+		CSV: {Name: "CSV", Description: "comma separated values", Extensions: []string{".csv"},
+			New: func(w io.Writer, o Options) TableEncoder {
+				e := NewCSVTableEncoder(w)
+				e.SetDelimiter(o.Delimiter)
+				e.SetQuoting(o.Quoting)
+				return e
+			}},
+		TSV: {Name: "TSV", Description: "tab separated values", Extensions: []string{".tsv", ".tab"},
+			New: func(w io.Writer, o Options) TableEncoder {
+				e := NewTSVTableEncoder(w)
+				e.SetDelimiter(o.Delimiter)
+				e.SetQuoting(o.Quoting)
+				return e
+			}},
+		JSON: {Name: "JSON", Description: "array of JSON objects", Extensions: []string{".json"},
+			New: func(w io.Writer, o Options) TableEncoder { return NewJSONTableEncoder(w) }},
+		JSONL: {Name: "JSONL", Aliases: []string{"NDJSON", "JSONLines"}, Description: "a JSON object on each line", Extensions: []string{".jsonl", ".ndjson"},
+			New: func(w io.Writer, o Options) TableEncoder { return NewJSONLTableEncoder(w) }},
+		Markdown: {Name: "Markdown", Aliases: []string{"MD", "GFM"}, Description: "GitHub Flavored Markdown table", Extensions: []string{".md", ".markdown"},
+			New: func(w io.Writer, o Options) TableEncoder { return NewMarkdownTableEncoder(w) }},
+		AsciiDoc: {Name: "AsciiDoc", Aliases: []string{"ADoc"}, Description: "AsciiDoc table", Extensions: []string{".adoc", ".asciidoc"},
+			New: func(w io.Writer, o Options) TableEncoder { return NewAsciiDocTableEncoder(w) }},
+		RST: {Name: "RST", Aliases: []string{"reStructuredText"}, Description: "reStructuredText grid table", Extensions: []string{".rst"},
+			New: func(w io.Writer, o Options) TableEncoder { return NewRSTTableEncoder(w) }},
+		HTML: {Name: "HTML", Description: "standalone HTML report", Extensions: []string{".html", ".htm"},
+			New: func(w io.Writer, o Options) TableEncoder { return NewHTMLTableEncoder(w) }},
+		XLSX: {Name: "XLSX", Aliases: []string{"Excel"}, Description: "Office Open XML workbook", Extensions: []string{".xlsx"}, Binary: true,
+			New: func(w io.Writer, o Options) TableEncoder { return NewXLSXTableEncoder(w) }},
+		Stream: {Name: "Stream", Description: "fixed-width columns written as rows arrive",
+			New: func(w io.Writer, o Options) TableEncoder { return NewStreamTableEncoder(w) }},
+		Template: {Name: "Template", Description: "Go template, tab separated values by default",
+			New: func(w io.Writer, o Options) TableEncoder { return NewTemplateTableEncoder(w, o.Template) }},
+		CBOR: {Name: "CBOR", Description: "CBOR sequence of maps, or of arrays without header", Extensions: []string{".cbor"}, Binary: true,
+			New: func(w io.Writer, o Options) TableEncoder { return NewCBORTableEncoder(w) }},
+		MessagePack: {Name: "MessagePack", Aliases: []string{"MsgPack"}, Description: "MessagePack maps, or arrays without header", Extensions: []string{".msgpack", ".mpk"}, Binary: true,
+			New: func(w io.Writer, o Options) TableEncoder { return NewMessagePackTableEncoder(w) }},
+	}
+)
+
+// Register adds a format to the registry. It panics if the name or an alias
+// is already registered, like database/sql.Register.
+func Register(f Format) FileType {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if f.New == nil {
+		panic("encoder: Register of " + f.Name + " without New")
+	}
+	for _, name := range append([]string{f.Name}, f.Aliases...) {
+		if _, ok := lookup(name); ok {
+			panic("encoder: Register called twice for " + name)
+		}
+	}
+	formats = append(formats, f)
+	return FileType(len(formats) - 1)
+}
+
+func lookup(name string) (FileType, bool) {
+	for i, f := range formats {
+		for _, n := range append([]string{f.Name}, f.Aliases...) {
+			if strings.EqualFold(n, name) {
+				return FileType(i), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Lookup finds a format by its name or alias, ignoring case.
+func Lookup(name string) (FileType, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	if f, ok := lookup(name); ok {
+		return f, nil
+	}
+	return 0, fmt.Errorf("invalid file type: %s", name)
+}
+
+// ByExtension finds a format by the extension of fileName, ignoring case.
+func ByExtension(fileName string) (FileType, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	ext := filepath.Ext(fileName)
+	for i, f := range formats {
+		for _, e := range f.Extensions {
+			if ext != "" && strings.EqualFold(e, ext) {
+				return FileType(i), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// FileTypes returns the registered formats in the order of registration.
+func FileTypes() []FileType {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	fileTypes := make([]FileType, len(formats))
+	for i := range formats {
+		fileTypes[i] = FileType(i)
+	}
+	return fileTypes
+}
+
+// Format returns the description of f, or nil if f isn't registered.
+func (f FileType) Format() *Format {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	if f < 0 || int(f) >= len(formats) {
+		return nil
+	}
+	format := formats[f]
+	return &format
+}
@@ -0,0 +1,100 @@
+package encoder_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+type upperEncoder struct {
+	*encoder.CSVTableEncoder
+}
+
+func TestRegister(t *testing.T) {
+	upper := encoder.Register(encoder.Format{
+		Name:        "Upper",
+		Aliases:     []string{"UpperCSV"},
+		Description: "CSV for the test",
+		Extensions:  []string{".ucsv"},
+		New:         upperEncoderNew,
+	})
+	if upper.String() != "Upper" {
+		t.Errorf("expected name is Upper, but actual name is %s", upper.String())
+	}
+	for _, name := range []string{"Upper", "uppercsv"} {
+		f, err := encoder.Lookup(name)
+		if err != nil || f != upper {
+			t.Errorf("Lookup(%q) returns (%v, %v)", name, f, err)
+		}
+	}
+	if f, ok := encoder.ByExtension("out.UCSV"); !ok || f != upper {
+		t.Errorf("ByExtension returns (%v, %v)", f, ok)
+	}
+	buf := &bytes.Buffer{}
+	e := upper.EncoderWithOptions(buf, encoder.Options{CRLF: true})
+	e.SetHeader([]string{"a"})
+	e.Append([]string{"b"})
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at Render (%v)", err)
+	}
+	if buf.String() != "a\r\nb\r\n" {
+		t.Errorf("expected \"a\\r\\nb\\r\\n\", but actual is %q", buf.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register of a registered alias doesn't panic")
+		}
+	}()
+	encoder.Register(encoder.Format{Name: "csv", New: upperEncoderNew})
+}
+
+func upperEncoderNew(w io.Writer, o encoder.Options) encoder.TableEncoder {
+	return upperEncoder{encoder.NewCSVTableEncoder(w)}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileType encoder.FileType
+	}{
+		{"CSV", encoder.CSV},
+		{"markdown", encoder.Markdown},
+		{"MD", encoder.Markdown},
+		{"ndjson", encoder.JSONL},
+		{"reStructuredText", encoder.RST},
+		{"None", encoder.None},
+	}
+	for _, tt := range tests {
+		f, err := encoder.Lookup(tt.name)
+		if err != nil || f != tt.fileType {
+			t.Errorf("Lookup(%q) returns (%v, %v), expected %v", tt.name, f, err, tt.fileType)
+		}
+	}
+	if _, err := encoder.Lookup("Foo"); err == nil {
+		t.Errorf("Lookup(\"Foo\") doesn't return error")
+	}
+}
+
+func TestByExtension(t *testing.T) {
+	tests := []struct {
+		fileName string
+		fileType encoder.FileType
+		ok       bool
+	}{
+		{"out.csv", encoder.CSV, true},
+		{"dir.d/out.JSONL", encoder.JSONL, true},
+		{"out.htm", encoder.HTML, true},
+		{"out.xlsx", encoder.XLSX, true},
+		{"out", 0, false},
+		{"out.unknown", 0, false},
+	}
+	for _, tt := range tests {
+		f, ok := encoder.ByExtension(tt.fileName)
+		if ok != tt.ok || (ok && f != tt.fileType) {
+			t.Errorf("ByExtension(%q) returns (%v, %v), expected (%v, %v)", tt.fileName, f, ok, tt.fileType, tt.ok)
+		}
+	}
+}
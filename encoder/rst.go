@@ -0,0 +1,105 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/moba1/usd/ucd"
+)
+
+// RSTTableEncoder writes a reStructuredText grid table.
+type RSTTableEncoder struct {
+	writer io.Writer
+	lines  [][]string
+	header []string
+}
+
+func NewRSTTableEncoder(w io.Writer) *RSTTableEncoder {
+	return &RSTTableEncoder{
+		writer: w,
+	}
+}
+
+func (rte *RSTTableEncoder) SetHeader(h []string) {
+	rte.header = h
+}
+
+func (rte *RSTTableEncoder) Append(row []string) {
+	rte.lines = append(rte.lines, row)
+}
+
+func (rte *RSTTableEncoder) AppendRecord(r CharRecord) {
+	rte.Append(r.Row())
+}
+
+var rstReplacer = newEscapeReplacer("\\`*_|", func(c rune) string {
+	return `\` + string(c)
+})
+
+// escapeRST escapes the inline markup of s, and the beginning of s which
+// would start a list or a section.
+func escapeRST(s string) string {
+	s = rstReplacer.Replace(singleLine(s))
+	if s != "" && strings.ContainsRune("-+#=.:•", []rune(s)[0]) {
+		s = `\` + s
+	}
+	return s
+}
+
+func (rte *RSTTableEncoder) Render() error {
+	n := len(rte.header)
+	if c := columns(rte.lines); c > n {
+		n = c
+	}
+	if n == 0 {
+		return nil
+	}
+	escape := func(row []string) []string {
+		cells := make([]string, n)
+		for i := range row {
+			cells[i] = escapeRST(row[i])
+		}
+		return cells
+	}
+	header := escape(rte.header)
+	lines := make([][]string, len(rte.lines))
+	for i, line := range rte.lines {
+		lines[i] = escape(line)
+	}
+
+	widths := make([]int, n)
+	for _, row := range append([][]string{header}, lines...) {
+		for i, cell := range row {
+			if w := ucd.DisplayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	var b strings.Builder
+	writeBorder := func(c string) {
+		for _, w := range widths {
+			b.WriteString("+" + strings.Repeat(c, w+2))
+		}
+		b.WriteString("+\n")
+	}
+	writeRow := func(row []string) {
+		for i, cell := range row {
+			b.WriteString("| " + cell + strings.Repeat(" ", widths[i]-ucd.DisplayWidth(cell)+1))
+		}
+		b.WriteString("|\n")
+	}
+	writeBorder("-")
+	if len(rte.header) > 0 {
+		writeRow(header)
+		writeBorder("=")
+	}
+	for _, line := range lines {
+		writeRow(line)
+		writeBorder("-")
+	}
+	if _, err := io.WriteString(rte.writer, b.String()); err != nil {
+		return fmt.Errorf("can't write rst table (reason; %s)", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+func TestRSTTableEncoder_Render(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rte := encoder.NewRSTTableEncoder(buf)
+	rte.SetHeader([]string{"Character", "Name"})
+	rte.Append([]string{"漢", "<CJK Ideograph>"})
+	rte.Append([]string{"|", "VERTICAL LINE"})
+	rte.Append([]string{"-", "HYPHEN-MINUS"})
+	if err := rte.Render(); err != nil {
+		t.Fatalf("error occured at RSTTableEncoder.Render (%v)", err)
+	}
+
+	expected := "+-----------+-----------------+\n" +
+		"| Character | Name            |\n" +
+		"+===========+=================+\n" +
+		"| 漢        | <CJK Ideograph> |\n" +
+		"+-----------+-----------------+\n" +
+		"| \\|        | VERTICAL LINE   |\n" +
+		"+-----------+-----------------+\n" +
+		"| \\-        | HYPHEN-MINUS    |\n" +
+		"+-----------+-----------------+\n"
+	if buf.String() != expected {
+		t.Errorf("expected RST table: %q, but RSTTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
+
+func TestRSTTableEncoder_RenderFormatCharacter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rte := encoder.NewRSTTableEncoder(buf)
+	rte.SetHeader([]string{"Text"})
+	rte.Append([]string{"a\u200db"})
+	if err := rte.Render(); err != nil {
+		t.Fatalf("error occured at RSTTableEncoder.Render (%v)", err)
+	}
+
+	// ZERO WIDTH JOINER takes no column
+	expected := "+------+\n" +
+		"| Text |\n" +
+		"+======+\n" +
+		"| a\u200db   |\n" +
+		"+------+\n"
+	if buf.String() != expected {
+		t.Errorf("expected RST table: %q, but RSTTableEncoder.Render return: %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,122 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/moba1/usd/ucd"
+)
+
+// streamColumnWidths are the widths of the common columns, wide enough for
+// most of their values.
+var streamColumnWidths = map[string]int{
+	"Character":   9,
+	"Code Point":  10,
+	"Code Points": 20,
+	"Name":        36,
+	"Hex":         19,
+	"File":        16,
+	"Line":        6,
+	"Column":      6,
+	"Offset":      10,
+	"Count":       8,
+}
+
+// StreamTableEncoder writes fixed-width columns as soon as each row is
+// appended. The width of a column is decided by the header and the first row,
+// and a wider value shifts the rest of its row.
+type StreamTableEncoder struct {
+	writer io.Writer
+	header []string
+	widths []int
+	err    error
+}
+
+func NewStreamTableEncoder(w io.Writer) *StreamTableEncoder {
+	return &StreamTableEncoder{
+		writer: w,
+	}
+}
+
+func (ste *StreamTableEncoder) SetHeader(h []string) {
+	ste.header = h
+}
+
+func (ste *StreamTableEncoder) writeRow(row []string) {
+	if ste.err != nil {
+		return
+	}
+	var b strings.Builder
+	for i, cell := range row {
+		cell = singleLine(cell)
+		b.WriteString(cell)
+		if i == len(row)-1 {
+			break
+		}
+		padding := 2
+		if i < len(ste.widths) {
+			padding += ste.widths[i] - ucd.DisplayWidth(cell)
+		}
+		if padding < 2 {
+			padding = 2
+		}
+		b.WriteString(strings.Repeat(" ", padding))
+	}
+	b.WriteString("\n")
+	if _, err := io.WriteString(ste.writer, b.String()); err != nil {
+		ste.err = fmt.Errorf("can't write table (reason; %s)", err.Error())
+	}
+}
+
+// start decides the widths and writes the header.
+func (ste *StreamTableEncoder) start(first []string) {
+	if ste.widths != nil {
+		return
+	}
+	n := len(ste.header)
+	if len(first) > n {
+		n = len(first)
+	}
+	ste.widths = make([]int, n)
+	for i := range ste.widths {
+		if i < len(ste.header) {
+			ste.widths[i] = ucd.DisplayWidth(ste.header[i])
+			if w := streamColumnWidths[ste.header[i]]; w > ste.widths[i] {
+				ste.widths[i] = w
+			}
+		}
+		if i < len(first) {
+			if w := ucd.DisplayWidth(singleLine(first[i])); w > ste.widths[i] {
+				ste.widths[i] = w
+			}
+		}
+	}
+	if len(ste.header) == 0 {
+		return
+	}
+	header := make([]string, len(ste.header))
+	rules := make([]string, len(ste.header))
+	for i, h := range ste.header {
+		header[i] = strings.ToUpper(h)
+		rules[i] = strings.Repeat("-", ste.widths[i])
+	}
+	ste.writeRow(header)
+	ste.writeRow(rules)
+}
+
+func (ste *StreamTableEncoder) Append(row []string) {
+	ste.start(row)
+	ste.writeRow(row)
+}
+
+func (ste *StreamTableEncoder) AppendRecord(r CharRecord) {
+	ste.Append(r.Row())
+}
+
+// Render writes the header of a table without rows and returns the first
+// error of Append.
+func (ste *StreamTableEncoder) Render() error {
+	ste.start(nil)
+	return ste.err
+}
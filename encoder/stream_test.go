@@ -0,0 +1,55 @@
+package encoder_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+)
+
+func TestStreamTableEncoder_Append(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ste := encoder.NewStreamTableEncoder(buf)
+	ste.SetHeader([]string{"Character", "Kind"})
+	ste.Append([]string{"漢", "Han"})
+	expected := "CHARACTER  KIND\n" +
+		"---------  ----\n" +
+		"漢         Han\n"
+	if buf.String() != expected {
+		t.Errorf("expected table: %q, but StreamTableEncoder.Append writes: %q", expected, buf.String())
+	}
+
+	// a wider value shifts the rest of its row
+	ste.Append([]string{"wider than 9", "x"})
+	expected += "wider than 9  x\n"
+	if err := ste.Render(); err != nil {
+		t.Fatalf("error occured at StreamTableEncoder.Render (%v)", err)
+	}
+	if buf.String() != expected {
+		t.Errorf("expected table: %q, but StreamTableEncoder writes: %q", expected, buf.String())
+	}
+}
+
+func TestStreamTableEncoder_RenderWithoutRows(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ste := encoder.NewStreamTableEncoder(buf)
+	ste.SetHeader([]string{"A"})
+	if err := ste.Render(); err != nil {
+		t.Fatalf("error occured at StreamTableEncoder.Render (%v)", err)
+	}
+	if buf.String() != "A\n-\n" {
+		t.Errorf("expected table: %q, but StreamTableEncoder.Render writes: %q", "A\n-\n", buf.String())
+	}
+}
+
+func TestStreamingEncoders_Append(t *testing.T) {
+	for _, f := range []encoder.FileType{encoder.CSV, encoder.TSV, encoder.JSON, encoder.JSONL, encoder.Markdown, encoder.AsciiDoc, encoder.Stream} {
+		buf := &bytes.Buffer{}
+		e := f.Encoder(buf)
+		e.SetHeader([]string{"Character"})
+		e.Append([]string{"streamed"})
+		if !bytes.Contains(buf.Bytes(), []byte("streamed")) {
+			t.Errorf("the encoder of file type %d doesn't write the row in Append: %q", f, buf.String())
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package encoder
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/moba1/usd/ucd"
+)
+
+// templateFuncs are the functions available in the templates.
+var templateFuncs = template.FuncMap{
+	// hex formats an integer as "1F600" and bytes as "F09F9880".
+	"hex": func(v interface{}) (string, error) {
+		switch v := v.(type) {
+		case []byte:
+			return fmt.Sprintf("%X", v), nil
+		case rune, byte, int, int64:
+			return fmt.Sprintf("%X", v), nil
+		}
+		return "", fmt.Errorf("hex of %T", v)
+	},
+	"quote": strconv.Quote,
+	"json": func(s string) (string, error) {
+		bs, err := marshalJSON(s)
+		return string(bs), err
+	},
+	"sql": func(s string) string {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	},
+	"padLeft": func(width int, s string) string {
+		if n := width - ucd.DisplayWidth(s); n > 0 {
+			return strings.Repeat(" ", n) + s
+		}
+		return s
+	},
+	"padRight": func(width int, s string) string {
+		if n := width - ucd.DisplayWidth(s); n > 0 {
+			return s + strings.Repeat(" ", n)
+		}
+		return s
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+var formatReplacer = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n")
+
+// NewFormat parses a template of a single record, which is written on its
+// own line. \t, \n and \\ in format are a tab, a line feed and a backslash.
+func NewFormat(format string) (*template.Template, error) {
+	t, err := template.New("record").Funcs(templateFuncs).Parse(formatReplacer.Replace(format) + "\n")
+	if err != nil {
+		return nil, fmt.Errorf("invalid format (reason; %s)", err.Error())
+	}
+	return t, nil
+}
+
+// ParseTemplateFile parses a template file. It may define "header" and
+// "footer" templates, and the record is its "record" template if defined or
+// the whole file.
+func ParseTemplateFile(fileName string) (*template.Template, error) {
+	t, err := template.New(filepath.Base(fileName)).Funcs(templateFuncs).ParseFiles(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse template (reason; %s)", err.Error())
+	}
+	return t, nil
+}
+
+// TemplateRecord is the data of a record. Row has the values in the order of
+// the header and Fields by the names of the header. The other fields are set
+// only for the characters of the dump subcommands.
+type TemplateRecord struct {
+	Index  int
+	Row    []string
+	Fields map[string]string
+
+	Character string
+	Rune      rune
+	CodePoint string
+	Name      string
+	Bytes     []byte
+	Hex       string
+	Offset    int64
+	Encoding  string
+	// Error is the message of a decoding or encoding error and Valid is
+	// false then. Character, Rune, CodePoint and Name are empty if the
+	// bytes failed to decode. CodePoint and Name of a grapheme cluster are
+	// those of its code points joined like Row, and Rune is 0.
+	Error string
+	Valid bool
+}
+
+// TemplateSummary is the data of the header and footer templates.
+type TemplateSummary struct {
+	Header []string
+	Count  int
+}
+
+// TemplateTableEncoder executes a template for each record as soon as it is
+// appended.
+type TemplateTableEncoder struct {
+	writer   io.Writer
+	template *template.Template
+	header   []string
+	count    int
+	started  bool
+	err      error
+}
+
+// NewTemplateTableEncoder returns an encoder of t, or of tab separated rows
+// if t is nil.
+func NewTemplateTableEncoder(w io.Writer, t *template.Template) *TemplateTableEncoder {
+	if t == nil {
+		t = template.Must(NewFormat(`{{join .Row "\t"}}`))
+	}
+	return &TemplateTableEncoder{
+		writer:   w,
+		template: t,
+	}
+}
+
+func (tte *TemplateTableEncoder) SetHeader(h []string) {
+	tte.header = h
+}
+
+func (tte *TemplateTableEncoder) execute(name string, data interface{}) {
+	if tte.err != nil {
+		return
+	}
+	t := tte.template.Lookup(name)
+	if t == nil {
+		if name != "record" {
+			return
+		}
+		t = tte.template
+	}
+	if err := t.Execute(tte.writer, data); err != nil {
+		tte.err = fmt.Errorf("can't execute template (reason; %s)", err.Error())
+	}
+}
+
+func (tte *TemplateTableEncoder) start() {
+	if tte.started {
+		return
+	}
+	tte.started = true
+	tte.execute("header", TemplateSummary{Header: tte.header})
+}
+
+func (tte *TemplateTableEncoder) record(row []string) TemplateRecord {
+	fields := map[string]string{}
+	for i, column := range tte.header {
+		if i < len(row) {
+			fields[column] = row[i]
+		}
+	}
+	return TemplateRecord{Index: tte.count, Row: row, Fields: fields}
+}
+
+func (tte *TemplateTableEncoder) Append(row []string) {
+	tte.start()
+	tte.execute("record", tte.record(row))
+	tte.count++
+}
+
+func (tte *TemplateTableEncoder) AppendRecord(r CharRecord) {
+	record := tte.record(r.Row())
+	record.Bytes = r.Bytes
+	record.Hex = HexString(r.Bytes)
+	record.Offset = r.Offset
+	record.Encoding = r.Encoding
+	if r.Err != nil {
+		record.Error = r.Err.Error()
+	} else {
+		record.Valid = true
+	}
+	if r.hasRune() {
+		record.Character = string(r.runes())
+		if len(r.Runes) == 0 {
+			record.Rune = r.Rune
+		}
+		record.CodePoint = record.Row[1]
+		record.Name = record.Row[2]
+	}
+	tte.start()
+	tte.execute("record", record)
+	tte.count++
+}
+
+// Render executes the footer template and returns the first error.
+func (tte *TemplateTableEncoder) Render() error {
+	tte.start()
+	tte.execute("footer", TemplateSummary{Header: tte.header, Count: tte.count})
+	return tte.err
+}
@@ -0,0 +1,88 @@
+package encoder_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+)
+
+func TestNewFormat(t *testing.T) {
+	tmpl, err := encoder.NewFormat(`{{.CodePoint}}\t{{padRight 4 (hex .Rune)}}|{{sql .Name}}|{{hex .Bytes}}|{{.Error}}`)
+	if err != nil {
+		t.Fatalf("NewFormat returns error: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	e := encoder.NewTemplateTableEncoder(buf, tmpl)
+	e.SetHeader(encoder.CharHeader)
+	e.AppendRecord(encoder.CharRecord{Rune: '\'', Bytes: []byte{0x27}})
+	e.AppendRecord(encoder.CharRecord{Rune: 0xFFFD, Bytes: []byte{0xFF}, Err: unicode.NewInvalidSequenceErr([]byte{0xFF})})
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at TemplateTableEncoder.Render (%v)", err)
+	}
+
+	expected := "U+0027\t27  |'APOSTROPHE'|27|\n" +
+		"\t0   |''|FF|invalid sequences: []byte{0xff}\n"
+	if buf.String() != expected {
+		t.Errorf("expected output: %q, but TemplateTableEncoder writes: %q", expected, buf.String())
+	}
+}
+
+func TestNewFormatError(t *testing.T) {
+	if _, err := encoder.NewFormat("{{.CodePoint"); err == nil {
+		t.Errorf("NewFormat with an unclosed action doesn't return error")
+	}
+}
+
+func TestParseTemplateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "yaml.tmpl")
+	text := `{{define "header"}}# {{join .Header ", "}}
+{{end}}{{define "record"}}- {{index .Fields "Code Point"}}: {{json (index .Fields "Name")}}
+{{end}}{{define "footer"}}# {{.Count}} rows
+{{end}}`
+	if err := ioutil.WriteFile(fileName, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := encoder.ParseTemplateFile(fileName)
+	if err != nil {
+		t.Fatalf("ParseTemplateFile returns error: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	e := encoder.NewTemplateTableEncoder(buf, tmpl)
+	e.SetHeader([]string{"Code Point", "Name"})
+	e.Append([]string{"U+0022", `QUOTATION "MARK"`})
+	expected := "# Code Point, Name\n- U+0022: \"QUOTATION \\\"MARK\\\"\"\n"
+	// records are written before Render
+	if buf.String() != expected {
+		t.Errorf("expected output: %q, but TemplateTableEncoder.Append writes: %q", expected, buf.String())
+	}
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at TemplateTableEncoder.Render (%v)", err)
+	}
+	expected += "# 1 rows\n"
+	if buf.String() != expected {
+		t.Errorf("expected output: %q, but TemplateTableEncoder writes: %q", expected, buf.String())
+	}
+}
+
+func TestTemplateTableEncoder_Default(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := encoder.Template.Encoder(buf)
+	e.Append([]string{"a", "b"})
+	if err := e.Render(); err != nil {
+		t.Fatalf("error occured at TemplateTableEncoder.Render (%v)", err)
+	}
+	if buf.String() != "a\tb\n" {
+		t.Errorf("expected output: %q, but TemplateTableEncoder writes: %q", "a\tb\n", buf.String())
+	}
+}
@@ -1,41 +1,21 @@
 package encoder
 
 import (
-	"fmt"
 	"io"
-	"strings"
 )
 
+// TSVTableEncoder writes each row as soon as it is appended.
 type TSVTableEncoder struct {
-	writer io.Writer
-	lines  [][]string
-	header []string
+	delimitedEncoder
 }
 
 func NewTSVTableEncoder(w io.Writer) *TSVTableEncoder {
 	return &TSVTableEncoder{
-		writer: w,
+		delimitedEncoder{
+			writer:    w,
+			delimiter: '\t',
+			quoting:   QuoteNone,
+			name:      "tsv",
+		},
 	}
 }
-
-func (tte *TSVTableEncoder) SetHeader(h []string) {
-	tte.header = h
-}
-
-func (tte *TSVTableEncoder) Append(row []string) {
-	tte.lines = append(tte.lines, row)
-}
-
-func (tte *TSVTableEncoder) Render() error {
-	if len(tte.header) > 0 {
-		if _, err := fmt.Fprintln(tte.writer, strings.Join(tte.header, "\t")); err != nil {
-			return fmt.Errorf("cannot write tsv header (reason; %s)", err.Error())
-		}
-	}
-	for _, line := range tte.lines {
-		if _, err := fmt.Fprintln(tte.writer, strings.Join(line, "\t")); err != nil {
-			return fmt.Errorf("cannot write tsv row (reason; %s)", err.Error())
-		}
-	}
-	return nil
-}
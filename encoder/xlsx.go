@@ -0,0 +1,198 @@
+package encoder
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// XLSXTableEncoder writes an Office Open XML workbook with a single sheet.
+// The header is frozen and has an autofilter, and the code points and the
+// integers of Extra of the records are numeric cells.
+type XLSXTableEncoder struct {
+	writer io.Writer
+	// lines are the cells, which are a string, or an int64 for a numeric
+	// cell.
+	lines  [][]interface{}
+	header []string
+}
+
+func NewXLSXTableEncoder(w io.Writer) *XLSXTableEncoder {
+	return &XLSXTableEncoder{
+		writer: w,
+	}
+}
+
+func (xte *XLSXTableEncoder) SetHeader(h []string) {
+	xte.header = h
+}
+
+func (xte *XLSXTableEncoder) Append(row []string) {
+	xte.lines = append(xte.lines, stringCells(row))
+}
+
+func (xte *XLSXTableEncoder) AppendRecord(r CharRecord) {
+	line := stringCells(r.Row())
+	if r.hasRune() && len(r.Runes) == 0 {
+		line[1] = int64(r.Rune)
+	}
+	for i, value := range r.Extra {
+		switch n := value.(type) {
+		case int:
+			line[len(CharHeader)+i] = int64(n)
+		case int64:
+			line[len(CharHeader)+i] = n
+		}
+	}
+	xte.lines = append(xte.lines, line)
+}
+
+func stringCells(row []string) []interface{} {
+	cells := make([]interface{}, len(row))
+	for i, value := range row {
+		cells[i] = value
+	}
+	return cells
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+	// the second cell format is the bold header
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>
+</styleSheet>`
+)
+
+// columnName returns the name of the column of index i such as "A" or "AA".
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func xmlText(s string) string {
+	var buf bytes.Buffer
+	// invalid characters of XML are replaced with U+FFFD
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func (xte *XLSXTableEncoder) Render() error {
+	n := len(xte.header)
+	for _, line := range xte.lines {
+		if len(line) > n {
+			n = len(line)
+		}
+	}
+	rows := len(xte.lines)
+	if len(xte.header) > 0 {
+		rows++
+	}
+
+	var workbook strings.Builder
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="usd" sheetId="1" r:id="rId1"/></sheets>
+`)
+	var sheet strings.Builder
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+`)
+	filterRange := ""
+	if len(xte.header) > 0 && n > 0 {
+		filterRange = fmt.Sprintf("A1:%s%d", columnName(n-1), rows)
+		fmt.Fprintf(&workbook, `<definedNames><definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">usd!$A$1:$%s$%d</definedName></definedNames>
+`, columnName(n-1), rows)
+		sheet.WriteString(`<sheetViews><sheetView tabSelected="1" workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>
+`)
+	}
+	workbook.WriteString("</workbook>")
+
+	sheet.WriteString("<sheetData>\n")
+	number := 0
+	writeRow := func(row []interface{}, cell func(i int, value interface{}) string) {
+		number++
+		fmt.Fprintf(&sheet, `<row r="%d">`, number)
+		for i, value := range row {
+			sheet.WriteString(cell(i, value))
+		}
+		sheet.WriteString("</row>\n")
+	}
+	stringCell := func(ref string, style string, value string) string {
+		return fmt.Sprintf(`<c r="%s" t="inlineStr"%s><is><t xml:space="preserve">%s</t></is></c>`, ref, style, xmlText(value))
+	}
+	if len(xte.header) > 0 {
+		writeRow(stringCells(xte.header), func(i int, value interface{}) string {
+			return stringCell(fmt.Sprintf("%s%d", columnName(i), number), ` s="1"`, value.(string))
+		})
+	}
+	for _, line := range xte.lines {
+		writeRow(line, func(i int, value interface{}) string {
+			ref := fmt.Sprintf("%s%d", columnName(i), number)
+			if n, ok := value.(int64); ok {
+				return fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, ref, strconv.FormatInt(n, 10))
+			}
+			if value == "" {
+				return ""
+			}
+			return stringCell(ref, "", value.(string))
+		})
+	}
+	sheet.WriteString("</sheetData>\n")
+	if filterRange != "" {
+		fmt.Fprintf(&sheet, "<autoFilter ref=\"%s\"/>\n", filterRange)
+	}
+	sheet.WriteString("</worksheet>")
+
+	archive := zip.NewWriter(xte.writer)
+	for _, part := range []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", sheet.String()},
+	} {
+		w, err := archive.Create(part.name)
+		if err == nil {
+			_, err = io.WriteString(w, part.content)
+		}
+		if err != nil {
+			return fmt.Errorf("can't write xlsx %s (reason; %s)", part.name, err.Error())
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("can't write xlsx (reason; %s)", err.Error())
+	}
+	return nil
+}
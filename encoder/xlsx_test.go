@@ -0,0 +1,87 @@
+package encoder_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+)
+
+func readXLSXPart(t *testing.T, xlsx []byte, name string) string {
+	archive, err := zip.NewReader(bytes.NewReader(xlsx), int64(len(xlsx)))
+	if err != nil {
+		t.Fatalf("XLSXTableEncoder.Render doesn't write a zip archive (%v)", err)
+	}
+	for _, f := range archive.File {
+		if f.Name != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("can't open %s (%v)", name, err)
+		}
+		defer r.Close()
+		bs, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("can't read %s (%v)", name, err)
+		}
+		return string(bs)
+	}
+	t.Fatalf("XLSXTableEncoder.Render doesn't write %s", name)
+	return ""
+}
+
+func TestXLSXTableEncoder_Render(t *testing.T) {
+	buf := &bytes.Buffer{}
+	xte := encoder.NewXLSXTableEncoder(buf)
+	xte.SetHeader(append(append([]string{}, encoder.CharHeader...), "Count"))
+	xte.AppendRecord(encoder.CharRecord{Rune: '<', Bytes: []byte{0x3C}, Extra: []interface{}{int64(2)}})
+	xte.AppendRecord(encoder.CharRecord{Rune: 0xFFFD, Bytes: []byte{0xFF}, Err: unicode.NewInvalidSequenceErr([]byte{0xFF}), Extra: []interface{}{int64(1)}})
+	if err := xte.Render(); err != nil {
+		t.Fatalf("error occured at XLSXTableEncoder.Render (%v)", err)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		readXLSXPart(t, buf.Bytes(), name)
+	}
+	workbook := readXLSXPart(t, buf.Bytes(), "xl/workbook.xml")
+	if !strings.Contains(workbook, "usd!$A$1:$E$3") {
+		t.Errorf("xl/workbook.xml doesn't define the filter range: %s", workbook)
+	}
+	sheet := readXLSXPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+	for _, expected := range []string{
+		`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`,
+		`<c r="B1" t="inlineStr" s="1"><is><t xml:space="preserve">Code Point</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">&lt;</t></is></c>`,
+		`<c r="B2"><v>60</v></c>`,
+		`<c r="E2"><v>2</v></c>`,
+		`<row r="3"><c r="C3" t="inlineStr">`,
+		`<autoFilter ref="A1:E3"/>`,
+	} {
+		if !strings.Contains(sheet, expected) {
+			t.Errorf("xl/worksheets/sheet1.xml doesn't contain %q", expected)
+		}
+	}
+}
+
+func TestXLSXTableEncoder_RenderWithoutHeader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	xte := encoder.NewXLSXTableEncoder(buf)
+	xte.Append([]string{"a", "1"})
+	if err := xte.Render(); err != nil {
+		t.Fatalf("error occured at XLSXTableEncoder.Render (%v)", err)
+	}
+
+	sheet := readXLSXPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+	if strings.Contains(sheet, "<pane") || strings.Contains(sheet, "<autoFilter") {
+		t.Errorf("xl/worksheets/sheet1.xml without header has a frozen pane or an autofilter: %s", sheet)
+	}
+	// columns without header are strings
+	if !strings.Contains(sheet, `<c r="B1" t="inlineStr"><is><t xml:space="preserve">1</t></is></c>`) {
+		t.Errorf("xl/worksheets/sheet1.xml doesn't contain the row: %s", sheet)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/moba1/usd/filter"
+)
+
+var rowFilter filter.Filter
+
+// filterFlags adds the options which select the rows of a dump subcommand.
+// Every option has a negated form and all options must be satisfied. Invalid
+// sequences are selected only by -invalidOnly and -validOnly.
+func filterFlags(fs *flag.FlagSet) {
+	fs.Func("only", "only `characters` of the class (value: ASCII|NonASCII)", func(s string) error {
+		switch s {
+		case "ASCII":
+			rowFilter.Add(filter.NonASCII(), true)
+		case "NonASCII":
+			rowFilter.Add(filter.NonASCII(), false)
+		default:
+			return fmt.Errorf("invalid class: %s", s)
+		}
+		return nil
+	})
+	listFlag := func(name string, negatedName string, usage string, condition func(string) (filter.Condition, error)) {
+		add := func(negate bool) func(string) error {
+			return func(s string) error {
+				cond, err := condition(s)
+				if err != nil {
+					return err
+				}
+				rowFilter.Add(cond, negate)
+				return nil
+			}
+		}
+		fs.Func(name, "only characters "+usage, add(false))
+		fs.Func(negatedName, "exclude characters "+usage, add(true))
+	}
+	listFlag("category", "notCategory", "of comma separated general `categories` (e.g. Cf,Zs or L)", filter.Categories)
+	listFlag("script", "notScript", "of comma separated `scripts` (e.g. Han,Hiragana)", filter.Scripts)
+	listFlag("range", "notRange", "in comma separated `ranges` (e.g. U+3000..U+30FF)", filter.Ranges)
+	listFlag("nameRegex", "notNameRegex", "whose name matches the case insensitive `regexp`", filter.NameRegexp)
+	fs.Var(boolFunc(func() {
+		rowFilter.OnlyInvalid()
+	}), "invalidOnly", "only invalid sequences")
+	fs.Var(boolFunc(func() {
+		rowFilter.ExcludeInvalid()
+	}), "validOnly", "exclude invalid sequences")
+}
+
+// boolFunc is a boolean flag which calls the function when it is set to true.
+type boolFunc func()
+
+func (f boolFunc) IsBoolFlag() bool { return true }
+
+func (f boolFunc) String() string { return "" }
+
+func (f boolFunc) Set(s string) error {
+	set, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	if set {
+		f()
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package filter selects the characters printed by the dump subcommands.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/moba1/usd/ucd"
+	"github.com/moba1/usd/unicode"
+)
+
+// Condition reports whether a valid character matches.
+type Condition func(c rune) bool
+
+// Filter accepts the characters which satisfy all of its conditions. The
+// conditions, negated or not, don't apply to invalid sequences, which are
+// accepted unless ExcludeInvalid is set, so that broken input is reported
+// whatever the filter is. The zero value accepts everything.
+type Filter struct {
+	conditions []Condition
+	invalid    invalidPolicy
+}
+
+type invalidPolicy int
+
+const (
+	keepInvalid invalidPolicy = iota
+	onlyInvalid
+	excludeInvalid
+)
+
+// Add adds a condition. When negate is true the characters which do not
+// match cond are accepted.
+func (f *Filter) Add(cond Condition, negate bool) {
+	if negate {
+		f.conditions = append(f.conditions, func(c rune) bool {
+			return !cond(c)
+		})
+		return
+	}
+	f.conditions = append(f.conditions, cond)
+}
+
+// OnlyInvalid makes f accept only invalid sequences.
+func (f *Filter) OnlyInvalid() {
+	f.invalid = onlyInvalid
+}
+
+// ExcludeInvalid makes f reject invalid sequences.
+func (f *Filter) ExcludeInvalid() {
+	f.invalid = excludeInvalid
+}
+
+// Accept reports whether c satisfies all conditions.
+func (f *Filter) Accept(c unicode.Char) bool {
+	if c.Err != nil {
+		return f.invalid != excludeInvalid
+	}
+	if f.invalid == onlyInvalid {
+		return false
+	}
+	for _, cond := range f.conditions {
+		if !cond(c.Rune) {
+			return false
+		}
+	}
+	return true
+}
+
+// NonASCII matches the characters above U+007F.
+func NonASCII() Condition {
+	return func(c rune) bool {
+		return c > 0x7F
+	}
+}
+
+// Categories matches a comma separated list of General_Category values such
+// as "Cf,Zs" or "L".
+func Categories(list string) (Condition, error) {
+	var categories []string
+	for _, name := range split(list) {
+		category, err := ucd.LookupCategory(name)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse category (reason; %s)", err.Error())
+		}
+		categories = append(categories, category)
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("no category is given")
+	}
+	return func(c rune) bool {
+		for _, category := range categories {
+			if ucd.MatchCategory(c, category) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// Scripts matches a comma separated list of scripts such as "Han,Hiragana".
+func Scripts(list string) (Condition, error) {
+	var scripts []string
+	for _, name := range split(list) {
+		script, err := ucd.LookupScript(name)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse script (reason; %s)", err.Error())
+		}
+		scripts = append(scripts, script)
+	}
+	if len(scripts) == 0 {
+		return nil, fmt.Errorf("no script is given")
+	}
+	return func(c rune) bool {
+		script := ucd.Script(c)
+		for _, s := range scripts {
+			if s == script {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// Ranges matches a comma separated list of ranges such as
+// "U+3000..U+30FF,U+FF01..U+FF5E".
+func Ranges(list string) (Condition, error) {
+	type bounds struct {
+		first, last rune
+	}
+	var ranges []bounds
+	for _, s := range split(list) {
+		first, last, err := ucd.ParseRange(s)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, bounds{first: first, last: last})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no range is given")
+	}
+	return func(c rune) bool {
+		for _, r := range ranges {
+			if r.first <= c && c <= r.last {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// NameRegexp matches the characters whose name or formal name alias matches
+// pattern, ignoring case.
+func NameRegexp(pattern string) (Condition, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name pattern: %s", pattern)
+	}
+	return func(c rune) bool {
+		if re.MatchString(ucd.Name(c)) {
+			return true
+		}
+		for _, alias := range ucd.Aliases(c) {
+			if re.MatchString(alias.Name) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func split(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
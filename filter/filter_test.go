@@ -0,0 +1,143 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/moba1/usd/filter"
+	"github.com/moba1/usd/unicode"
+)
+
+func chars(s string) []unicode.Char {
+	var cs []unicode.Char
+	for _, c := range s {
+		cs = append(cs, unicode.Char{Rune: c})
+	}
+	return cs
+}
+
+func accepted(f *filter.Filter, cs []unicode.Char) string {
+	var rs []rune
+	for _, c := range cs {
+		if f.Accept(c) {
+			rs = append(rs, c.Rune)
+		}
+	}
+	return string(rs)
+}
+
+func mustCondition(t *testing.T, cond filter.Condition, err error) filter.Condition {
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return cond
+}
+
+func TestFilter(t *testing.T) {
+	input := chars("a\u3000b\u200b漢あ字ｱ")
+	tests := []struct {
+		name     string
+		build    func(f *filter.Filter)
+		expected string
+	}{
+		{"empty", func(f *filter.Filter) {}, "a\u3000b\u200b漢あ字ｱ"},
+		{"non-ascii", func(f *filter.Filter) {
+			f.Add(filter.NonASCII(), false)
+		}, "\u3000\u200b漢あ字ｱ"},
+		{"ascii", func(f *filter.Filter) {
+			f.Add(filter.NonASCII(), true)
+		}, "ab"},
+		{"category", func(f *filter.Filter) {
+			cond, err := filter.Categories("Cf,Zs")
+			f.Add(mustCondition(t, cond, err), false)
+		}, "\u3000\u200b"},
+		{"not category", func(f *filter.Filter) {
+			cond, err := filter.Categories("L")
+			f.Add(mustCondition(t, cond, err), true)
+		}, "\u3000\u200b"},
+		{"script", func(f *filter.Filter) {
+			cond, err := filter.Scripts("han")
+			f.Add(mustCondition(t, cond, err), false)
+		}, "漢字"},
+		{"range", func(f *filter.Filter) {
+			cond, err := filter.Ranges("U+3000..U+30FF, U+FF61..U+FF9F")
+			f.Add(mustCondition(t, cond, err), false)
+		}, "\u3000あｱ"},
+		{"name", func(f *filter.Filter) {
+			cond, err := filter.NameRegexp("^hiragana|zwsp")
+			f.Add(mustCondition(t, cond, err), false)
+		}, "\u200bあ"},
+		{"combined", func(f *filter.Filter) {
+			f.Add(filter.NonASCII(), false)
+			cond, err := filter.Scripts("Han")
+			f.Add(mustCondition(t, cond, err), true)
+			cond, err = filter.Categories("Lo")
+			f.Add(mustCondition(t, cond, err), false)
+		}, "あｱ"},
+	}
+	for _, test := range tests {
+		f := &filter.Filter{}
+		test.build(f)
+		if actual := accepted(f, input); actual != test.expected {
+			t.Errorf("%s: Filter accepts %q, but expected value is %q", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	invalid := unicode.Char{Rune: 0xFFFD, Bytes: []byte{0xFF}, Err: unicode.NewInvalidSequenceErr([]byte{0xFF})}
+	valid := unicode.Char{Rune: 0xFFFD, Bytes: []byte{0xEF, 0xBF, 0xBD}}
+	latin := unicode.Char{Rune: 'a', Bytes: []byte{0x61}}
+
+	scripts, err := filter.Scripts("Latin")
+	scripts = mustCondition(t, scripts, err)
+	tests := []struct {
+		name                  string
+		build                 func(f *filter.Filter)
+		invalid, valid, latin bool
+	}{
+		{"empty", func(f *filter.Filter) {}, true, true, true},
+		{"property", func(f *filter.Filter) {
+			f.Add(filter.NonASCII(), false)
+		}, true, true, false},
+		{"negated property", func(f *filter.Filter) {
+			f.Add(scripts, true)
+		}, true, true, false},
+		{"only invalid", func(f *filter.Filter) {
+			f.OnlyInvalid()
+		}, true, false, false},
+		{"exclude invalid", func(f *filter.Filter) {
+			f.Add(scripts, true)
+			f.ExcludeInvalid()
+		}, false, true, false},
+	}
+	for _, test := range tests {
+		f := &filter.Filter{}
+		test.build(f)
+		for _, c := range []struct {
+			char     unicode.Char
+			expected bool
+		}{{invalid, test.invalid}, {valid, test.valid}, {latin, test.latin}} {
+			if actual := f.Accept(c.char); actual != c.expected {
+				t.Errorf("%s: Accept(%x) returns %v, but expected value is %v", test.name, c.char.Bytes, actual, c.expected)
+			}
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := filter.Scripts("Klingon"); err == nil {
+		t.Errorf("Scripts(%q) doesn't return error", "Klingon")
+	}
+	if _, err := filter.Ranges("U+30FF..U+3000"); err == nil {
+		t.Errorf("Ranges(%q) doesn't return error", "U+30FF..U+3000")
+	}
+	if _, err := filter.NameRegexp("("); err == nil {
+		t.Errorf("NameRegexp(%q) doesn't return error", "(")
+	}
+	if _, err := filter.Categories(","); err == nil {
+		t.Errorf("Categories(%q) doesn't return error", ",")
+	}
+	if _, err := filter.Categories("Lu,Zz"); err == nil {
+		t.Errorf("Categories(%q) doesn't return error", "Lu,Zz")
+	}
+}
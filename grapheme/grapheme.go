@@ -0,0 +1,138 @@
+// Package grapheme finds extended grapheme cluster boundaries as described in
+// UAX #29.
+package grapheme
+
+import (
+	_ "embed"
+	"sync"
+
+	"github.com/moba1/usd/ucd"
+)
+
+//go:embed data/GraphemeBreakProperty.txt
+var graphemeBreakPropertyTxt string
+
+//go:embed data/ExtendedPictographic.txt
+var extendedPictographicTxt string
+
+var (
+	loadOnce sync.Once
+	breaks   ucd.RangeTable
+	pictures ucd.RangeTable
+)
+
+const (
+	hangulFirst = 0xAC00
+	hangulLast  = 0xD7A3
+)
+
+func load() {
+	loadOnce.Do(func() {
+		breaks = ucd.ParseRangeTable(graphemeBreakPropertyTxt)
+		pictures = ucd.ParseRangeTable(extendedPictographicTxt)
+	})
+}
+
+// BreakProperty returns the Grapheme_Cluster_Break property value of c, such
+// as "Extend". Characters without a value are "Other".
+func BreakProperty(c rune) string {
+	if c >= hangulFirst && c <= hangulLast {
+		if (c-hangulFirst)%28 == 0 {
+			return "LV"
+		}
+		return "LVT"
+	}
+	load()
+	if value, ok := breaks.Lookup(c); ok {
+		return value
+	}
+	return "Other"
+}
+
+// IsExtendedPictographic reports whether c has the Extended_Pictographic
+// property.
+func IsExtendedPictographic(c rune) bool {
+	load()
+	_, ok := pictures.Lookup(c)
+	return ok
+}
+
+// Splitter finds the boundaries of a stream of characters.
+type Splitter struct {
+	started bool
+	prev    string
+	// pictographic is true after Extended_Pictographic Extend* and
+	// emojiZWJ after Extended_Pictographic Extend* ZWJ (rule GB11).
+	pictographic bool
+	emojiZWJ     bool
+	// regionalIndicators is the number of Regional_Indicator characters
+	// before the current one (rules GB12 and GB13).
+	regionalIndicators int
+}
+
+// IsBoundary reports whether there is a grapheme cluster boundary before c,
+// the next character of the stream. It is true for the first character.
+func (s *Splitter) IsBoundary(c rune) bool {
+	next := BreakProperty(c)
+	boundary := !s.started || s.isBoundary(next, IsExtendedPictographic(c))
+
+	switch {
+	case IsExtendedPictographic(c):
+		s.pictographic, s.emojiZWJ = true, false
+	case next == "Extend":
+		s.emojiZWJ = false
+	case next == "ZWJ":
+		s.emojiZWJ, s.pictographic = s.pictographic, false
+	default:
+		s.pictographic, s.emojiZWJ = false, false
+	}
+	if next == "Regional_Indicator" {
+		s.regionalIndicators++
+	} else {
+		s.regionalIndicators = 0
+	}
+	s.started = true
+	s.prev = next
+	return boundary
+}
+
+func (s *Splitter) isBoundary(next string, pictographic bool) bool {
+	prev := s.prev
+	switch {
+	case prev == "CR" && next == "LF": // GB3
+		return false
+	case prev == "Control" || prev == "CR" || prev == "LF": // GB4
+		return true
+	case next == "Control" || next == "CR" || next == "LF": // GB5
+		return true
+	case prev == "L" && (next == "L" || next == "V" || next == "LV" || next == "LVT"): // GB6
+		return false
+	case (prev == "LV" || prev == "V") && (next == "V" || next == "T"): // GB7
+		return false
+	case (prev == "LVT" || prev == "T") && next == "T": // GB8
+		return false
+	case next == "Extend" || next == "ZWJ": // GB9
+		return false
+	case next == "SpacingMark": // GB9a
+		return false
+	case prev == "Prepend": // GB9b
+		return false
+	case s.emojiZWJ && pictographic: // GB11
+		return false
+	case prev == "Regional_Indicator" && next == "Regional_Indicator": // GB12 and GB13
+		return s.regionalIndicators%2 == 0
+	}
+	return true // GB999
+}
+
+// Count returns the number of grapheme clusters of s.
+func Count(s string) int {
+	var splitter Splitter
+	n := 0
+	for _, c := range s {
+		if splitter.IsBoundary(c) {
+			n++
+		}
+	}
+	return n
+}
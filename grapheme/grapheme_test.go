@@ -0,0 +1,51 @@
+package grapheme_test
+
+import (
+	"testing"
+
+	"github.com/moba1/usd/grapheme"
+)
+
+func TestBreakProperty(t *testing.T) {
+	testCases := map[rune]string{
+		'a':    "Other",
+		'\r':   "CR",
+		0x0301: "Extend",
+		0x200D: "ZWJ",
+		0x1100: "L",
+		0xAC00: "LV",
+		0xAC01: "LVT",
+		0x0903: "SpacingMark",
+		0x0600: "Prepend",
+	}
+	for c, expected := range testCases {
+		if value := grapheme.BreakProperty(c); value != expected {
+			t.Errorf("grapheme.BreakProperty(%U) returns %q, but expected value is %q", c, value, expected)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	testCases := []struct {
+		text  string
+		count int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"e\u0301", 1},
+		{"\r\n\n", 2},
+		{"\u1100\u1161\u11A8\uAC01", 2},
+		// family: man, ZWJ, woman, ZWJ, girl
+		{"\U0001F468\u200D\U0001F469\u200D\U0001F467", 1},
+		{"\U0001F44D\U0001F3FD!", 2},
+		// flags of Japan and France, and a lone regional indicator
+		{"\U0001F1EF\U0001F1F5\U0001F1EB\U0001F1F7\U0001F1EF", 3},
+		{"a\u200D\U0001F469", 2},
+		{"\u0600a", 1},
+	}
+	for _, tc := range testCases {
+		if count := grapheme.Count(tc.text); count != tc.count {
+			t.Errorf("grapheme.Count(%+q) returns %d, but expected value is %d", tc.text, count, tc.count)
+		}
+	}
+}
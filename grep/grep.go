@@ -0,0 +1,153 @@
+// Package grep matches regular expressions against lines of decoded
+// characters.
+package grep
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moba1/usd/ucd"
+	"github.com/moba1/usd/unicode"
+)
+
+// Compile compiles expr with the syntax of the regexp package. Unicode
+// blocks can be used like scripts and categories: \p{InHiragana} or
+// \p{Block=CJK Symbols and Punctuation} matches a character of the block and
+// \P{...} a character outside of it, also in a character class.
+func Compile(expr string, ignoreCase bool) (*regexp.Regexp, error) {
+	expanded, err := expandBlocks(expr)
+	if err != nil {
+		return nil, err
+	}
+	if ignoreCase {
+		expanded = "(?i)" + expanded
+	}
+	re, err := regexp.Compile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %s (reason; %s)", expr, err.Error())
+	}
+	return re, nil
+}
+
+// expandBlocks replaces the block classes of expr with code point ranges.
+func expandBlocks(expr string) (string, error) {
+	var b strings.Builder
+	inClass := false
+	for i := 0; i < len(expr); {
+		switch {
+		case expr[i] == '\\' && i+1 < len(expr):
+			if name, n, ok := blockClass(expr[i:]); ok {
+				block, err := ucd.LookupBlock(name)
+				if err != nil {
+					return "", err
+				}
+				b.WriteString(blockRanges(block, expr[i+1] == 'P', inClass))
+				i += n
+				continue
+			}
+			_, size := utf8.DecodeRuneInString(expr[i+1:])
+			b.WriteString(expr[i : i+1+size])
+			i += 1 + size
+			continue
+		case inClass && strings.HasPrefix(expr[i:], "[:"):
+			if end := strings.Index(expr[i:], ":]"); end >= 0 {
+				b.WriteString(expr[i : i+end+2])
+				i += end + 2
+				continue
+			}
+		case !inClass && expr[i] == '[':
+			inClass = true
+			b.WriteByte('[')
+			i++
+			// a ] at the beginning of a class is a literal
+			if strings.HasPrefix(expr[i:], "^") {
+				b.WriteByte('^')
+				i++
+			}
+			if strings.HasPrefix(expr[i:], "]") {
+				b.WriteByte(']')
+				i++
+			}
+			continue
+		case inClass && expr[i] == ']':
+			inClass = false
+		}
+		b.WriteByte(expr[i])
+		i++
+	}
+	return b.String(), nil
+}
+
+// blockClass parses \p{InName}, \p{Block=Name} or \p{Blk=Name} at the
+// beginning of s and returns the block name and the length of the class.
+func blockClass(s string) (string, int, bool) {
+	if len(s) < 3 || (s[1] != 'p' && s[1] != 'P') || s[2] != '{' {
+		return "", 0, false
+	}
+	end := strings.IndexByte(s, '}')
+	if end < 0 {
+		return "", 0, false
+	}
+	name := s[3:end]
+	for _, prefix := range []string{"Block=", "Blk="} {
+		if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+			return name[len(prefix):], end + 1, true
+		}
+	}
+	if strings.HasPrefix(name, "In") {
+		// scripts such as Inherited are left to the regexp package
+		if _, err := ucd.LookupBlock(name[2:]); err == nil {
+			return name[2:], end + 1, true
+		}
+	}
+	return "", 0, false
+}
+
+func blockRanges(block ucd.Block, negate bool, inClass bool) string {
+	var ranges string
+	if !negate {
+		ranges = fmt.Sprintf(`\x{%X}-\x{%X}`, block.First, block.Last)
+	} else {
+		if block.First > 0 {
+			ranges += fmt.Sprintf(`\x{0}-\x{%X}`, block.First-1)
+		}
+		if block.Last < utf8.MaxRune {
+			ranges += fmt.Sprintf(`\x{%X}-\x{%X}`, block.Last+1, utf8.MaxRune)
+		}
+	}
+	if inClass {
+		return ranges
+	}
+	return "[" + ranges + "]"
+}
+
+// Match is a match in a line. Start and End are the indexes of the first
+// character and the character after the last one.
+type Match struct {
+	Start int
+	End   int
+}
+
+// FindAll returns the non-empty matches of re in line. Invalid sequences are
+// matched as U+FFFD.
+func FindAll(re *regexp.Regexp, line []unicode.Char) []Match {
+	var text strings.Builder
+	// index maps a byte index of text to the index of its character
+	index := map[int]int{}
+	for i, c := range line {
+		index[text.Len()] = i
+		text.WriteRune(c.Rune)
+	}
+	index[text.Len()] = len(line)
+
+	var matches []Match
+	for _, loc := range re.FindAllStringIndex(text.String(), -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		matches = append(matches, Match{Start: index[loc[0]], End: index[loc[1]]})
+	}
+	return matches
+}
@@ -0,0 +1,86 @@
+package grep_test
+
+import (
+	"testing"
+
+	"github.com/moba1/usd/grep"
+	"github.com/moba1/usd/unicode"
+)
+
+func chars(s string) []unicode.Char {
+	var cs []unicode.Char
+	for _, c := range s {
+		cs = append(cs, unicode.Char{Rune: c})
+	}
+	return cs
+}
+
+func find(t *testing.T, expr string, ignoreCase bool, s string) []string {
+	re, err := grep.Compile(expr, ignoreCase)
+	if err != nil {
+		t.Fatalf("Compile(%q) returns error: %v", expr, err)
+	}
+	line := []rune(s)
+	var found []string
+	for _, m := range grep.FindAll(re, chars(s)) {
+		found = append(found, string(line[m.Start:m.End]))
+	}
+	return found
+}
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		expr       string
+		ignoreCase bool
+		input      string
+		expected   []string
+	}{
+		{`\p{Han}+`, false, "漢字とかなと漢", []string{"漢字", "漢"}},
+		{`[\x{E000}-\x{F8FF}]`, false, "a\ue000b\uf8ffc", []string{"\ue000", "\uf8ff"}},
+		{`\p{InHiragana}+`, false, "漢字とかなカナ", []string{"とかな"}},
+		{`\p{Block=Katakana}`, false, "かなカナ", []string{"カ", "ナ"}},
+		{`[\p{InHiragana}\p{blk=katakana}]+`, false, "漢かなカナ字", []string{"かなカナ"}},
+		{`\P{InBasic_Latin}+`, false, "abc漢字def", []string{"漢字"}},
+		{`[^\P{InBasic_Latin}]+`, false, "abc漢字def", []string{"abc", "def"}},
+		{`\p{Inherited}`, false, "e\u0301", []string{"\u0301"}},
+		{`[]\p{InHiragana}]+`, false, "a]か]b", []string{"]か]"}},
+		{`[[:digit:]\p{InHiragana}]+`, false, "x1か2y", []string{"1か2"}},
+		{`straße`, true, "STRASSE Straße", []string{"Straße"}},
+		{`x*`, false, "ab", nil},
+	}
+	for _, test := range tests {
+		actual := find(t, test.expr, test.ignoreCase, test.input)
+		if len(actual) != len(test.expected) {
+			t.Errorf("FindAll(%q) in %q returns %q, but expected value is %q", test.expr, test.input, actual, test.expected)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Errorf("FindAll(%q) in %q returns %q, but expected value is %q", test.expr, test.input, actual, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestFindAllInvalid(t *testing.T) {
+	re, err := grep.Compile(`b\x{FFFD}c`, false)
+	if err != nil {
+		t.Fatalf("Compile returns error: %v", err)
+	}
+	line := chars("ab")
+	line = append(line, unicode.Char{Rune: 0xFFFD, Bytes: []byte{0xFF}, Err: unicode.NewInvalidSequenceErr([]byte{0xFF})})
+	line = append(line, chars("c")...)
+	matches := grep.FindAll(re, line)
+	if len(matches) != 1 || matches[0].Start != 1 || matches[0].End != 4 {
+		t.Errorf("FindAll returns %+v, but expected value is [{Start:1 End:4}]", matches)
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	for _, expr := range []string{`\p{Block=Klingon}`, `(`} {
+		if _, err := grep.Compile(expr, false); err == nil {
+			t.Errorf("Compile(%q) doesn't return error", expr)
+		}
+	}
+}
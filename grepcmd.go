@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/grep"
+	"github.com/moba1/usd/unicode"
+)
+
+const grepCmdName = "grep"
+
+func parseGrepCmd(args []string) func() error {
+	grepCmd := flag.NewFlagSet(grepCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(grepCmd)
+	var (
+		ignoreCase, count, filesWithMatches bool
+		before, after, context              int
+	)
+	grepCmd.BoolVar(&ignoreCase, "i", false, "ignore case")
+	grepCmd.BoolVar(&count, "c", false, "print the number of matching lines of each file")
+	grepCmd.BoolVar(&filesWithMatches, "l", false, "print only the names of the files with matches")
+	grepCmd.IntVar(&before, "B", 0, "print `num` lines before each matching line")
+	grepCmd.IntVar(&after, "A", 0, "print `num` lines after each matching line")
+	grepCmd.IntVar(&context, "C", 0, "print `num` lines before and after each matching line")
+	grepCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", grepCmdName),
+			fmt.Sprintf("  %s [option] pattern [file]...", grepCmdName),
+			"The pattern is a regular expression of Go (https://golang.org/s/re2syntax).",
+			`Blocks are matched by \p{InHiragana} or \p{Block=CJK Symbols and Punctuation}.`,
+			"Standard input is read when no file is given.",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(grepCmd.Output(), stmt)
+		}
+		grepCmd.PrintDefaults()
+	}
+	if err := grepCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+	if grepCmd.NArg() == 0 {
+		grepCmd.Usage()
+		os.Exit(2)
+	}
+	if before < context {
+		before = context
+	}
+	if after < context {
+		after = context
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		re, err := grep.Compile(grepCmd.Arg(0), ignoreCase)
+		if err != nil {
+			return err
+		}
+		showContext := before > 0 || after > 0
+
+		table := newTableEncoder(tableOutput)
+		if !noHeader {
+			switch {
+			case filesWithMatches:
+				table.SetHeader([]string{"File"})
+			case count:
+				table.SetHeader([]string{"File", "Count"})
+			case showContext:
+				table.SetHeader([]string{"File", "Line", "Column", "Offset", "Match", "Code Points", "Text"})
+			default:
+				table.SetHeader([]string{"File", "Line", "Column", "Offset", "Match", "Code Points"})
+			}
+		}
+
+		search := func(fileName string, r io.Reader) error {
+			var (
+				matchingLines int
+				lineNumber    int
+				// lines before the current one which may be printed as context
+				previous  [][]unicode.Char
+				lastShown int
+				afterLeft int
+			)
+			showLine := func(number int, line []unicode.Char) {
+				table.Append([]string{fileName, strconv.Itoa(number), "", "", "", "", lineText(line)})
+				lastShown = number
+			}
+			err := scanLines(r, cs, func(line []unicode.Char) error {
+				lineNumber++
+				matches := grep.FindAll(re, line)
+				if len(matches) == 0 {
+					if showContext && afterLeft > 0 {
+						showLine(lineNumber, line)
+						afterLeft--
+					} else if before > 0 {
+						previous = append(previous, line)
+						if len(previous) > before {
+							previous = previous[1:]
+						}
+					}
+					return nil
+				}
+
+				matchingLines++
+				if count || filesWithMatches {
+					return nil
+				}
+				for i, l := range previous {
+					if number := lineNumber - len(previous) + i; number > lastShown {
+						showLine(number, l)
+					}
+				}
+				previous = nil
+				for _, m := range matches {
+					row := matchRow(fileName, line[m.Start:m.End])
+					if showContext {
+						row = append(row, lineText(line))
+					}
+					table.Append(row)
+				}
+				lastShown = lineNumber
+				afterLeft = after
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("can't read %s (reason; %s)", fileName, err.Error())
+			}
+			switch {
+			case filesWithMatches && matchingLines > 0:
+				table.Append([]string{fileName})
+			case count:
+				table.Append([]string{fileName, strconv.Itoa(matchingLines)})
+			}
+			return nil
+		}
+
+		err = readFiles(grepCmd.Args()[1:], search)
+		if renderErr := table.Render(); err == nil {
+			err = renderErr
+		}
+		return err
+	}
+}
+
+func matchRow(fileName string, match []unicode.Char) []string {
+	var characters strings.Builder
+	codePoints := make([]string, len(match))
+	for i, c := range match {
+		characters.WriteString(encoder.GraphicString(c.Rune))
+		codePoints[i] = fmt.Sprintf("%U", c.Rune)
+	}
+	first := match[0]
+	return []string{
+		fileName,
+		strconv.Itoa(first.Line),
+		strconv.Itoa(first.Column),
+		strconv.FormatInt(first.Offset, 10),
+		characters.String(),
+		strings.Join(codePoints, " "),
+	}
+}
+
+func lineText(line []unicode.Char) string {
+	rs := make([]rune, len(line))
+	for i, c := range line {
+		rs[i] = c.Rune
+	}
+	return string(rs)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/moba1/usd/hidden"
+	"github.com/moba1/usd/unicode"
+)
+
+const hiddenCmdName = "hidden"
+
+func parseHiddenCmd(args []string) func() error {
+	hiddenCmd := flag.NewFlagSet(hiddenCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(hiddenCmd)
+	hiddenCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", hiddenCmdName),
+			fmt.Sprintf("  %s [option] [file]...", hiddenCmdName),
+			"Standard input is checked when no file is given.",
+			"Exits with non-zero status when hidden text is found.",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(hiddenCmd.Output(), stmt)
+		}
+		hiddenCmd.PrintDefaults()
+	}
+	if err := hiddenCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		table := newTableEncoder(tableOutput)
+		if !noHeader {
+			table.SetHeader([]string{"File", "Line", "Column", "Offset", "Kind", "Length", "Visible Text", "Hidden Text"})
+		}
+		count := 0
+		err = readFiles(hiddenCmd.Args(), func(fileName string, r io.Reader) error {
+			return scanLines(r, cs, func(line []unicode.Char) error {
+				runes := make([]rune, len(line))
+				for i, c := range line {
+					if c.Err != nil {
+						return fmt.Errorf("can't read %s at line %d, column %d (reason; %s)", fileName, c.Line, c.Column, c.Err.Error())
+					}
+					runes[i] = c.Rune
+				}
+				runs := hidden.Find(runes)
+				if len(runs) == 0 {
+					return nil
+				}
+
+				var visible strings.Builder
+				next := 0
+				for _, run := range runs {
+					visible.WriteString(string(runes[next:run.Start]))
+					next = run.End
+				}
+				visible.WriteString(string(runes[next:]))
+				for _, run := range runs {
+					start := line[run.Start]
+					table.Append([]string{
+						fileName,
+						strconv.Itoa(start.Line),
+						strconv.Itoa(start.Column),
+						strconv.FormatInt(start.Offset, 10),
+						run.Kind.String(),
+						strconv.Itoa(run.End - run.Start),
+						visible.String(),
+						strconv.Quote(string(run.Payload)),
+					})
+					count++
+				}
+				return nil
+			})
+		})
+		if renderErr := table.Render(); err == nil {
+			err = renderErr
+		}
+		if err == nil && count > 0 {
+			err = fmt.Errorf("%s found %d hidden texts", hiddenCmdName, count)
+		}
+		return err
+	}
+}
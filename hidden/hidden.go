@@ -0,0 +1,130 @@
+// Package hidden finds text hidden in invisible characters: ASCII encoded as
+// tag characters and bytes encoded as variation selectors.
+package hidden
+
+import (
+	"unicode"
+)
+
+// Kind is the encoding of hidden text.
+type Kind int
+
+const (
+	// Tag is a run of tag characters (U+E0000..U+E007F), which map to ASCII.
+	Tag Kind = iota
+	// VariationSelector is a run of variation selectors, each of which
+	// encodes a byte: U+FE00..U+FE0F are 0 to 15 and U+E0100..U+E01EF are 16
+	// to 255.
+	VariationSelector
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Tag:
+		return "Tag"
+	case VariationSelector:
+		return "VariationSelector"
+	}
+	return "Unknown"
+}
+
+// Run is hidden text found by Find.
+type Run struct {
+	Kind Kind
+	// Start and End are the indexes of the first character and the one after
+	// the last character of the run.
+	Start int
+	End   int
+	// Payload is the decoded text.
+	Payload []byte
+}
+
+const (
+	cancelTag = 0xE007F
+	blackFlag = 0x1F3F4
+)
+
+// TagValue returns the ASCII character which the tag character c maps to.
+func TagValue(c rune) (byte, bool) {
+	if c >= 0xE0020 && c <= 0xE007E {
+		return byte(c - 0xE0000), true
+	}
+	return 0, false
+}
+
+func isTag(c rune) bool {
+	return c >= 0xE0000 && c <= 0xE007F
+}
+
+// VariationSelectorValue returns the byte which the variation selector c
+// encodes.
+func VariationSelectorValue(c rune) (byte, bool) {
+	switch {
+	case c >= 0xFE00 && c <= 0xFE0F:
+		return byte(c - 0xFE00), true
+	case c >= 0xE0100 && c <= 0xE01EF:
+		return byte(c - 0xE0100 + 16), true
+	}
+	return 0, false
+}
+
+// isEmojiTagSequence reports whether runes[start:end] is the tag sequence of
+// an emoji flag of a subdivision, such as England.
+func isEmojiTagSequence(runes []rune, start, end int) bool {
+	if start == 0 || runes[start-1] != blackFlag || runes[end-1] != cancelTag || end-start < 2 {
+		return false
+	}
+	for _, c := range runes[start : end-1] {
+		if !(c >= 0xE0061 && c <= 0xE007A) && !(c >= 0xE0030 && c <= 0xE0039) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the runs of hidden text in runes. Tag sequences of emoji flags
+// are ignored, and so are single variation selectors except those of the
+// supplement which don't follow an ideograph.
+func Find(runes []rune) []Run {
+	var runs []Run
+	for i := 0; i < len(runes); i++ {
+		start := i
+		switch {
+		case isTag(runes[i]):
+			for i < len(runes) && isTag(runes[i]) {
+				i++
+			}
+			if isEmojiTagSequence(runes, start, i) {
+				i--
+				continue
+			}
+			run := Run{Kind: Tag, Start: start, End: i}
+			for _, c := range runes[start:i] {
+				if b, ok := TagValue(c); ok {
+					run.Payload = append(run.Payload, b)
+				}
+			}
+			runs = append(runs, run)
+			i--
+		default:
+			if _, ok := VariationSelectorValue(runes[i]); !ok {
+				continue
+			}
+			run := Run{Kind: VariationSelector, Start: start}
+			for ; i < len(runes); i++ {
+				b, ok := VariationSelectorValue(runes[i])
+				if !ok {
+					break
+				}
+				run.Payload = append(run.Payload, b)
+			}
+			run.End = i
+			i--
+			if run.End-run.Start == 1 && (runes[start] < 0xE0100 || (start > 0 && unicode.Is(unicode.Ideographic, runes[start-1]))) {
+				continue
+			}
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
@@ -12,21 +12,125 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/moba1/usd/charset"
 	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/hidden"
 	"github.com/moba1/usd/unicode"
-	"golang.org/x/text/unicode/runenames"
 )
 
 //go:embed version
 var version string
 
 var (
-	reader      func(*bufio.Reader) (rune, []byte, error)
-	fileType    encoder.FileType
-	noHeader    bool
-	showVersion bool
+	reader func(*bufio.Reader) (rune, []byte, error)
+	// readerEncoding is the name of the encoding of reader such as "UTF-16LE"
+	readerEncoding string
+	fileType       encoder.FileType
+	noHeader       bool
+	showVersion    bool
+	showHidden     bool
+	command        func() error
 )
 
+func parseEndian(endianHolder *unicode.Endian, s string) error {
+	switch s {
+	case "Big":
+		*endianHolder = unicode.BigEndian
+	case "Little":
+		*endianHolder = unicode.LittleEndian
+	default:
+		return fmt.Errorf("invalid endian: %s", s)
+	}
+	return nil
+}
+
+func endianSuffix(endian unicode.Endian) string {
+	if endian == unicode.LittleEndian {
+		return "LE"
+	}
+	return "BE"
+}
+
+func parseCharset(charsetHolder *string, s string) error {
+	for _, name := range charset.Names() {
+		if s == name {
+			*charsetHolder = s
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid charset: %s", s)
+}
+
+// charsetFlags defines -encoding and -endian on fs. The returned function
+// looks up the charset after fs is parsed.
+func charsetFlags(fs *flag.FlagSet) func() (*charset.Charset, error) {
+	var (
+		name   = "UTF8"
+		endian = unicode.BigEndian
+	)
+	fs.Func("encoding", fmt.Sprintf("character `encoding`. default is 'UTF8' (value: %s)", strings.Join(charset.Names(), "|")), func(s string) error {
+		return parseCharset(&name, s)
+	})
+	fs.Func("endian", "UTF16 and UTF32 `endian`. default is 'Big' (value: Big|Little)", func(s string) error {
+		return parseEndian(&endian, s)
+	})
+	return func() (*charset.Charset, error) {
+		return charset.Lookup(name, endian)
+	}
+}
+
+// scanLines calls fn with the characters of each line of r, without the line
+// terminator.
+func scanLines(r io.Reader, cs *charset.Charset, fn func(line []unicode.Char) error) error {
+	scanner := unicode.NewScanner(r, cs.Read)
+	var line []unicode.Char
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if c.Err != nil || c.Rune != '\n' {
+			line = append(line, c)
+			continue
+		}
+		if n := len(line); n > 0 && line[n-1].Err == nil && line[n-1].Rune == '\r' {
+			line = line[:n-1]
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+		line = nil
+	}
+	if len(line) > 0 {
+		return fn(line)
+	}
+	return nil
+}
+
+// readFiles calls fn with each of the files, or with the standard input named
+// "-" when no file is given.
+func readFiles(fileNames []string, fn func(fileName string, r io.Reader) error) error {
+	if len(fileNames) == 0 {
+		return fn("-", os.Stdin)
+	}
+	for _, fileName := range fileNames {
+		file, err := os.Open(fileName)
+		if err != nil {
+			return fmt.Errorf("can't open %s (reason; %s)", fileName, err.Error())
+		}
+		err = fn(fileName, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var dumpHeader = encoder.CharHeader
+
 func init() {
 	const (
 		utf8CmdName  = "utf8"
@@ -47,6 +151,30 @@ func init() {
 			"        dump UTF-16",
 			fmt.Sprintf("  %s", utf32CmdName),
 			"        dump UTF-32",
+			fmt.Sprintf("  %s", transcodeCmdName),
+			"        convert between character encodings",
+			fmt.Sprintf("  %s", searchCmdName),
+			"        search characters by name",
+			fmt.Sprintf("  %s", cpCmdName),
+			"        describe code points",
+			fmt.Sprintf("  %s", rangeCmdName),
+			"        list code points in a range",
+			fmt.Sprintf("  %s", securityCmdName),
+			"        check identifiers for confusable and restricted characters",
+			fmt.Sprintf("  %s", bidiCheckCmdName),
+			"        find bidirectional controls and hidden zero width characters",
+			fmt.Sprintf("  %s", bidiCmdName),
+			"        show the visual order of each line",
+			fmt.Sprintf("  %s", whitespaceCmdName),
+			"        find invisible and non-ASCII whitespace characters",
+			fmt.Sprintf("  %s", hiddenCmdName),
+			"        find text hidden in tag characters and variation selectors",
+			fmt.Sprintf("  %s", sanitizeCmdName),
+			"        remove and replace problematic characters",
+			fmt.Sprintf("  %s", grepCmdName),
+			"        search files for a Unicode regular expression",
+			fmt.Sprintf("  %s", completionCmdName),
+			"        print a shell completion script",
 			"Options:",
 			"  -help",
 			"       show help",
@@ -57,21 +185,10 @@ func init() {
 		flag.PrintDefaults()
 	}
 	flag.BoolVar(&showVersion, "version", false, "show version")
-	flag.Func("fileType", "output file type. default is None (value: CSV|TSV|None)", func(s string) error {
-		switch s {
-		case "CSV":
-			fileType = encoder.CSV
-		case "TSV":
-			fileType = encoder.TSV
-		case "None":
-			fileType = encoder.None
-		default:
-			return fmt.Errorf("invalid file type: %s", s)
-		}
-		return nil
-	})
 	flag.BoolVar(&noHeader, "noHeader", false, "no header")
+	setOutputOptions := outputFlags(flag.CommandLine)
 	flag.Parse()
+	setOutputOptions()
 	if showVersion {
 		fmt.Println(version)
 		os.Exit(0)
@@ -92,17 +209,6 @@ func init() {
 		subCmd = args[0]
 		subCmdArgs = args[1:]
 	}
-	var parseEndian = func(endianHolder *unicode.Endian, s string) error {
-		switch s {
-		case "Big":
-			*endianHolder = unicode.BigEndian
-		case "Little":
-			*endianHolder = unicode.LittleEndian
-		default:
-			return fmt.Errorf("invalid endian: %s", s)
-		}
-		return nil
-	}
 	switch subCmd {
 	case utf8CmdName:
 		utf8Cmd.Usage = func() {
@@ -118,10 +224,18 @@ func init() {
 			}
 			utf8Cmd.PrintDefaults()
 		}
+		revealFlag(utf8Cmd)
+		summaryFlags(utf8Cmd)
+		uniqueFlags(utf8Cmd)
+		filterFlags(utf8Cmd)
+		utf8Cmd.BoolVar(&showHidden, "hidden", false, "annotate text hidden in tag characters and variation selectors. can't be used with -reveal, -summary and -unique")
 		if err := utf8Cmd.Parse(subCmdArgs); err != nil {
 			log.Fatalln(err)
 		}
+		checkDumpFlags()
 		reader = unicode.ReadUtf8Char
+		readerEncoding = "UTF-8"
+		command = dump
 	case utf16CmdName:
 		var endian unicode.Endian = unicode.BigEndian
 		utf16Cmd.Func("endian", "UTF16 `endian`. default is 'Big' (value: Big|Little)", func(s string) error {
@@ -140,12 +254,20 @@ func init() {
 			}
 			utf16Cmd.PrintDefaults()
 		}
+		revealFlag(utf16Cmd)
+		summaryFlags(utf16Cmd)
+		uniqueFlags(utf16Cmd)
+		filterFlags(utf16Cmd)
+		utf16Cmd.BoolVar(&showHidden, "hidden", false, "annotate text hidden in tag characters and variation selectors. can't be used with -reveal, -summary and -unique")
 		if err := utf16Cmd.Parse(subCmdArgs); err != nil {
 			log.Fatalln(err)
 		}
+		checkDumpFlags()
 		reader = func(buf *bufio.Reader) (rune, []byte, error) {
 			return unicode.ReadUtf16Char(endian, buf)
 		}
+		readerEncoding = "UTF-16" + endianSuffix(endian)
+		command = dump
 	case utf32CmdName:
 		var endian unicode.Endian = unicode.BigEndian
 		utf32Cmd.Func("endian", "UTF32 `endian`. default is 'Big' (value: Big|Little)", func(s string) error {
@@ -164,12 +286,44 @@ func init() {
 			}
 			utf32Cmd.PrintDefaults()
 		}
+		revealFlag(utf32Cmd)
+		summaryFlags(utf32Cmd)
+		uniqueFlags(utf32Cmd)
+		filterFlags(utf32Cmd)
+		utf32Cmd.BoolVar(&showHidden, "hidden", false, "annotate text hidden in tag characters and variation selectors. can't be used with -reveal, -summary and -unique")
 		if err := utf32Cmd.Parse(subCmdArgs); err != nil {
 			log.Fatalln(err)
 		}
+		checkDumpFlags()
 		reader = func(buf *bufio.Reader) (rune, []byte, error) {
 			return unicode.ReadUtf32Char(endian, buf)
 		}
+		readerEncoding = "UTF-32" + endianSuffix(endian)
+		command = dump
+	case transcodeCmdName:
+		command = parseTranscodeCmd(subCmdArgs)
+	case searchCmdName:
+		command = parseSearchCmd(subCmdArgs)
+	case cpCmdName:
+		command = parseCpCmd(subCmdArgs)
+	case rangeCmdName:
+		command = parseRangeCmd(subCmdArgs)
+	case securityCmdName:
+		command = parseSecurityCmd(subCmdArgs)
+	case bidiCheckCmdName:
+		command = parseBidiCheckCmd(subCmdArgs)
+	case bidiCmdName:
+		command = parseBidiCmd(subCmdArgs)
+	case whitespaceCmdName:
+		command = parseWhitespaceCmd(subCmdArgs)
+	case hiddenCmdName:
+		command = parseHiddenCmd(subCmdArgs)
+	case sanitizeCmdName:
+		command = parseSanitizeCmd(subCmdArgs)
+	case grepCmdName:
+		command = parseGrepCmd(subCmdArgs)
+	case completionCmdName:
+		command = parseCompletionCmd(subCmdArgs)
 	default:
 		println(fmt.Sprintf("invalid command: %s", subCmd))
 		os.Exit(1)
@@ -177,40 +331,103 @@ func init() {
 }
 
 func main() {
-	runeTable := fileType.Encoder(os.Stdout)
+	err := command()
+	if err == nil {
+		// the file of -o is created even if the command writes nothing
+		_, err = tableOutput.Write(nil)
+	}
+	if closeErr := closeTableOutput(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// checkDumpFlags rejects the options of the dump subcommands which can't be
+// used together or have invalid values.
+func checkDumpFlags() {
+	checkSummaryFlags()
+	if !showHidden {
+		return
+	}
+	switch {
+	case revealMode != nil:
+		log.Fatalln("-hidden can't be used with -reveal")
+	case showSummary:
+		log.Fatalln("-hidden can't be used with -summary")
+	case uniqueBy != "":
+		log.Fatalln("-hidden can't be used with -unique")
+	}
+}
+
+func dump() error {
+	if revealMode != nil {
+		return revealText()
+	}
+	if showSummary {
+		return summarize()
+	}
+	if uniqueBy != "" {
+		return dumpUnique()
+	}
+
+	runeTable := newTableEncoder(tableOutput)
 	if !noHeader {
-		runeTable.SetHeader([]string{"Character", "Code Point", "Name", "Hex"})
+		header := dumpHeader
+		if showHidden {
+			header = append(append([]string{}, dumpHeader...), "Hidden Text")
+		}
+		runeTable.SetHeader(header)
 	}
 
-	buf := bufio.NewReader(os.Stdin)
+	// records are kept only for -hidden, which needs the characters after
+	// them
+	var (
+		records []encoder.CharRecord
+		runes   []rune
+		chars   []unicode.Char
+	)
+	scanner := unicode.NewScanner(os.Stdin, reader)
 	for {
-		c, bs, err := reader(buf)
+		c, err := scanner.Scan()
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			fmt.Println(err)
-			return
+			return fmt.Errorf("can't read input (reason; %s)", err.Error())
 		}
 
-		toHexString := func(bs []byte) string {
-			hexes := []string{}
-			for _, b := range bs {
-				hexes = append(hexes, fmt.Sprintf("0x%02X", b))
-			}
-			return strings.Join(hexes, " ")
+		// invalid sequences are rows whose record has Err, so that the
+		// streaming encoders finish their documents
+		record := encoder.CharRecord{
+			Rune:     c.Rune,
+			Bytes:    c.Bytes,
+			Offset:   c.Offset,
+			Encoding: readerEncoding,
+			Err:      c.Err,
 		}
-		graphic := strings.Trim(strconv.QuoteRuneToGraphic(c), "'")
-		if c == '\'' {
-			graphic = "'"
+		if !showHidden {
+			if rowFilter.Accept(c) {
+				runeTable.AppendRecord(record)
+			}
+			continue
 		}
-		runeTable.Append([]string{
-			graphic,
-			fmt.Sprintf("%U", c),
-			runenames.Name(c),
-			toHexString(bs),
-		})
+		records = append(records, record)
+		runes = append(runes, c.Rune)
+		chars = append(chars, c)
 	}
-	if err := runeTable.Render(); err != nil {
-		log.Fatalln(err)
+	// the hidden text is null for the characters which don't start a run
+	for i := range records {
+		records[i].Extra = []interface{}{nil}
+	}
+	// the whole run is annotated at its first character
+	for _, run := range hidden.Find(runes) {
+		records[run.Start].Extra[0] = strconv.Quote(string(run.Payload))
+	}
+	for i, record := range records {
+		if rowFilter.Accept(chars[i]) {
+			runeTable.AppendRecord(record)
+		}
 	}
+	return runeTable.Render()
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+)
+
+var (
+	outputOptions encoder.Options
+	// tableOutput is the standard output or the file of -o. Every subcommand
+	// writes its output to it.
+	tableOutput      io.Writer = os.Stdout
+	closeTableOutput           = func() error { return nil }
+)
+
+// outputFile is the file of -o. It is created at the first write, so a
+// command which fails before writing anything leaves an existing file as it
+// is.
+type outputFile struct {
+	name string
+	file *os.File
+}
+
+func (f *outputFile) Write(p []byte) (int, error) {
+	if f.file == nil {
+		file, err := os.Create(f.name)
+		if err != nil {
+			return 0, fmt.Errorf("can't create %s (reason; %s)", f.name, err.Error())
+		}
+		f.file = file
+	}
+	return f.file.Write(p)
+}
+
+func (f *outputFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+// newTextOutput returns the writer of the output which isn't a table, such
+// as a chart, with the character encoding, byte order mark and line endings
+// of the output options. flush must be called after the text is written.
+func newTextOutput() (w io.Writer, flush func() error) {
+	tw := encoder.NewTextWriter(tableOutput, outputOptions)
+	return tw, tw.Flush
+}
+
+func fileTypeNames() []string {
+	var names []string
+	for _, f := range encoder.FileTypes() {
+		names = append(names, f.String())
+	}
+	return names
+}
+
+// outputFlags defines the options of the table encoders on fs. The returned
+// function sets outputOptions after fs is parsed.
+func outputFlags(fs *flag.FlagSet) func() {
+	var (
+		name   = "UTF8"
+		endian = unicode.BigEndian
+	)
+	fs.Func("outputEncoding", fmt.Sprintf("character `encoding` of the output. default is 'UTF8' (value: %s)", strings.Join(charset.Names(), "|")), func(s string) error {
+		return parseCharset(&name, s)
+	})
+	fs.Func("outputEndian", "UTF16 and UTF32 `endian` of the output. default is 'Big' (value: Big|Little)", func(s string) error {
+		return parseEndian(&endian, s)
+	})
+	fs.BoolVar(&outputOptions.BOM, "bom", false, "write the byte order mark of the output encoding")
+	fs.BoolVar(&outputOptions.CRLF, "crlf", false, "write CRLF line endings")
+	fs.Func("delimiter", "field `delimiter` of CSV and TSV, a character or \\t", func(s string) error {
+		if s == `\t` {
+			s = "\t"
+		}
+		if utf8.RuneCountInString(s) != 1 {
+			return fmt.Errorf("invalid delimiter: %s", s)
+		}
+		outputOptions.Delimiter, _ = utf8.DecodeRuneInString(s)
+		return nil
+	})
+	fs.Func("quote", "quoting `policy` of CSV and TSV. default is 'Minimal' for CSV and 'None' for TSV (value: Minimal|All|None)", func(s string) error {
+		switch s {
+		case "Minimal":
+			outputOptions.Quoting = encoder.QuoteMinimal
+		case "All":
+			outputOptions.Quoting = encoder.QuoteAll
+		case "None":
+			outputOptions.Quoting = encoder.QuoteNone
+		default:
+			return fmt.Errorf("invalid quoting policy: %s", s)
+		}
+		return nil
+	})
+	fileTypeSet := false
+	fs.Func("fileType", fmt.Sprintf("output file type. default is None (value: %s)", strings.Join(fileTypeNames(), "|")), func(s string) error {
+		f, err := encoder.Lookup(s)
+		if err != nil {
+			return err
+		}
+		fileType = f
+		fileTypeSet = true
+		return nil
+	})
+	var outputFileName string
+	fs.StringVar(&outputFileName, "o", "", "write the output to `file`. its extension selects the file type unless -fileType is given")
+	var format, templateFile string
+	fs.StringVar(&format, "format", "", "write each row with a Go `template` such as '{{.CodePoint}}\\t{{.Name}}'")
+	fs.StringVar(&templateFile, "template", "", "write the rows with the Go template `file`, which may define \"header\", \"record\" and \"footer\"")
+	return func() {
+		var err error
+		switch {
+		case format != "" && templateFile != "":
+			log.Fatalln("-format and -template can't be used together")
+		case format != "":
+			outputOptions.Template, err = encoder.NewFormat(format)
+		case templateFile != "":
+			outputOptions.Template, err = encoder.ParseTemplateFile(templateFile)
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if outputOptions.Template != nil {
+			fileType = encoder.Template
+		} else if f, ok := encoder.ByExtension(outputFileName); ok && !fileTypeSet {
+			fileType = f
+		}
+		if outputFileName != "" {
+			file := &outputFile{name: outputFileName}
+			tableOutput = file
+			closeTableOutput = file.Close
+		}
+		if name == "UTF8" {
+			return
+		}
+		cs, err := charset.Lookup(name, endian)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		outputOptions.Charset = cs
+	}
+}
+
+func newTableEncoder(w io.Writer) encoder.TableEncoder {
+	return fileType.EncoderWithOptions(w, outputOptions)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moba1/usd/reveal"
+	"github.com/moba1/usd/unicode"
+)
+
+var revealMode *reveal.Mode
+
+// revealFlag adds the -reveal option to a dump subcommand.
+func revealFlag(fs *flag.FlagSet) {
+	fs.Func("reveal", "print the text with markers for `characters` instead of the table (value: Invisible|NonASCII)", func(s string) error {
+		var mode reveal.Mode
+		switch s {
+		case "Invisible":
+			mode = reveal.Invisible
+		case "NonASCII":
+			mode = reveal.NonASCII
+		default:
+			return fmt.Errorf("invalid characters to reveal: %s", s)
+		}
+		revealMode = &mode
+		return nil
+	})
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func revealText() error {
+	text, flush := newTextOutput()
+	out := bufio.NewWriter(text)
+	color := isTerminal(tableOutput) && os.Getenv("NO_COLOR") == ""
+	w := reveal.NewWriter(out, *revealMode, color)
+	scanner := unicode.NewScanner(os.Stdin, reader)
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			out.Flush()
+			flush()
+			return fmt.Errorf("can't read input (reason; %s)", err.Error())
+		}
+		if err := w.Write(c); err != nil {
+			return err
+		}
+	}
+	err := out.Flush()
+	if flushErr := flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		return fmt.Errorf("can't write output (reason; %s)", err.Error())
+	}
+	return nil
+}
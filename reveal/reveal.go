@@ -0,0 +1,96 @@
+// Package reveal writes text as it is, except that the characters which are
+// hard to see are replaced with inline markers such as
+// ⟨U+200B ZERO WIDTH SPACE⟩.
+package reveal
+
+import (
+	"fmt"
+	"io"
+	stdunicode "unicode"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/ucd"
+	"github.com/moba1/usd/unicode"
+	"github.com/moba1/usd/whitespace"
+)
+
+// Mode decides which characters are replaced with markers.
+type Mode int
+
+const (
+	// Invisible marks invalid input and the characters which are
+	// non-printable or invisible.
+	Invisible Mode = iota
+	// NonASCII also marks every other non-ASCII character.
+	NonASCII
+)
+
+const (
+	colorInvalid   = "\x1b[1;31m"
+	colorInvisible = "\x1b[1;33m"
+	colorNonASCII  = "\x1b[36m"
+	colorReset     = "\x1b[0m"
+)
+
+type Writer struct {
+	w     io.Writer
+	mode  Mode
+	color bool
+}
+
+// NewWriter returns a Writer which writes to w in UTF-8. Markers are coloured
+// with ANSI escape sequences when color is true.
+func NewWriter(w io.Writer, mode Mode, color bool) *Writer {
+	return &Writer{w: w, mode: mode, color: color}
+}
+
+func isInvisible(c rune) bool {
+	if c == '\n' || c == '\t' {
+		return false
+	}
+	if _, ok := whitespace.Classify(c); ok {
+		return true
+	}
+	return !stdunicode.IsPrint(c)
+}
+
+// Marker returns the marker of a character, or false when the character is
+// written as it is.
+func Marker(c unicode.Char, mode Mode) (string, bool) {
+	if c.Err != nil {
+		return fmt.Sprintf("⟨invalid: %s⟩", encoder.HexString(c.Bytes)), true
+	}
+	if !isInvisible(c.Rune) && (mode != NonASCII || c.Rune < 0x80) {
+		return "", false
+	}
+	name := ucd.Name(c.Rune)
+	if aliases := ucd.Aliases(c.Rune); name == "" && len(aliases) > 0 {
+		name = aliases[0].Name
+	}
+	if name == "" {
+		return fmt.Sprintf("⟨%U⟩", c.Rune), true
+	}
+	return fmt.Sprintf("⟨%U %s⟩", c.Rune, name), true
+}
+
+// Write writes c or its marker.
+func (w *Writer) Write(c unicode.Char) error {
+	marker, ok := Marker(c, w.mode)
+	var s string
+	switch {
+	case !ok:
+		s = string(c.Rune)
+	case !w.color:
+		s = marker
+	case c.Err != nil:
+		s = colorInvalid + marker + colorReset
+	case isInvisible(c.Rune):
+		s = colorInvisible + marker + colorReset
+	default:
+		s = colorNonASCII + marker + colorReset
+	}
+	if _, err := io.WriteString(w.w, s); err != nil {
+		return fmt.Errorf("can't write output (reason; %s)", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package reveal_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/moba1/usd/reveal"
+	"github.com/moba1/usd/unicode"
+)
+
+func write(t *testing.T, input []byte, mode reveal.Mode, color bool) string {
+	var output bytes.Buffer
+	w := reveal.NewWriter(&output, mode, color)
+	scanner := unicode.NewScanner(bytes.NewBuffer(input), unicode.ReadUtf8Char)
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Scanner.Scan returns error: %v", err)
+		}
+		if err := w.Write(c); err != nil {
+			t.Fatalf("Writer.Write returns error: %v", err)
+		}
+	}
+	return output.String()
+}
+
+func TestWriter_Write(t *testing.T) {
+	testCases := []struct {
+		input  []byte
+		mode   reveal.Mode
+		color  bool
+		output string
+	}{
+		{[]byte("a\u200Bb\tあ\n"), reveal.Invisible, false, "a⟨U+200B ZERO WIDTH SPACE⟩b\tあ\n"},
+		{[]byte("a\u00A0\x07\uE000"), reveal.Invisible, false, "a⟨U+00A0 NO-BREAK SPACE⟩⟨U+0007 ALERT⟩⟨U+E000⟩"},
+		{[]byte{'a', 0xFE, 0xE3, 0x81}, reveal.Invisible, false, "a⟨invalid: 0xFE⟩⟨invalid: 0xE3 0x81⟩"},
+		{[]byte("a\u00E9\u200B"), reveal.NonASCII, false, "a⟨U+00E9 LATIN SMALL LETTER E WITH ACUTE⟩⟨U+200B ZERO WIDTH SPACE⟩"},
+		{[]byte("\u00E9\u200B\xFE"), reveal.NonASCII, true, "\x1b[36m⟨U+00E9 LATIN SMALL LETTER E WITH ACUTE⟩\x1b[0m\x1b[1;33m⟨U+200B ZERO WIDTH SPACE⟩\x1b[0m\x1b[1;31m⟨invalid: 0xFE⟩\x1b[0m"},
+	}
+	for _, tc := range testCases {
+		if output := write(t, tc.input, tc.mode, tc.color); output != tc.output {
+			t.Errorf("Writer.Write writes %q for %q, but expected value is %q", output, tc.input, tc.output)
+		}
+	}
+}
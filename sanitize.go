@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/moba1/usd/sanitize"
+)
+
+const sanitizeCmdName = "sanitize"
+
+func parseSanitizeCmd(args []string) func() error {
+	sanitizeCmd := flag.NewFlagSet(sanitizeCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(sanitizeCmd)
+	var rules sanitize.Rules
+	sanitizeCmd.BoolVar(&rules.StripBidi, "stripBidi", false, "strip bidirectional controls")
+	sanitizeCmd.Func("invisible", "`policy` for invisible characters. default is 'Keep' (value: Keep|Remove|Replace)", func(s string) error {
+		switch s {
+		case "Keep":
+			rules.Invisible = sanitize.KeepInvisible
+		case "Remove":
+			rules.Invisible = sanitize.RemoveInvisible
+		case "Replace":
+			rules.Invisible = sanitize.ReplaceInvisible
+		default:
+			return fmt.Errorf("invalid policy: %s", s)
+		}
+		return nil
+	})
+	sanitizeCmd.BoolVar(&rules.FoldWidth, "foldWidth", false, "fold full-width ASCII variants to ASCII")
+	sanitizeCmd.BoolVar(&rules.Skeleton, "skeleton", false, "replace non-ASCII confusables with their skeleton")
+	sanitizeCmd.Func("normalize", "normalization `form`. default is 'None' (value: None|NFC|NFKC)", func(s string) error {
+		switch s {
+		case "None":
+			rules.Normalization = sanitize.NoNormalization
+		case "NFC":
+			rules.Normalization = sanitize.NFC
+		case "NFKC":
+			rules.Normalization = sanitize.NFKC
+		default:
+			return fmt.Errorf("invalid normalization form: %s", s)
+		}
+		return nil
+	})
+	sanitizeCmd.Func("lineEnding", "line `ending`. default is 'Keep' (value: Keep|LF|CRLF|CR)", func(s string) error {
+		switch s {
+		case "Keep":
+			rules.LineEnding = sanitize.KeepLineEnding
+		case "LF":
+			rules.LineEnding = sanitize.LF
+		case "CRLF":
+			rules.LineEnding = sanitize.CRLF
+		case "CR":
+			rules.LineEnding = sanitize.CR
+		default:
+			return fmt.Errorf("invalid line ending: %s", s)
+		}
+		return nil
+	})
+	sanitizeCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", sanitizeCmdName),
+			fmt.Sprintf("  %s [option]", sanitizeCmdName),
+			"The sanitized text is written in the input encoding and the changes to stderr.",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(sanitizeCmd.Output(), stmt)
+		}
+		sanitizeCmd.PrintDefaults()
+	}
+	if err := sanitizeCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		sanitizer := sanitize.Sanitizer{
+			Charset: cs,
+			Rules:   rules,
+		}
+		changeTable := newTableEncoder(os.Stderr)
+		if !noHeader {
+			changeTable.SetHeader([]string{"Offset", "Input", "Output", "Reason"})
+		}
+		err = sanitizer.Sanitize(os.Stdin, tableOutput, func(c sanitize.Change) {
+			changeTable.Append([]string{
+				strconv.FormatInt(c.Offset, 10),
+				strconv.Quote(c.Input),
+				strconv.Quote(c.Output),
+				c.Reason,
+			})
+		})
+		if renderErr := changeTable.Render(); err == nil {
+			err = renderErr
+		}
+		return err
+	}
+}
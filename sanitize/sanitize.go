@@ -0,0 +1,267 @@
+// Package sanitize removes and replaces the characters which usd reports as
+// problems.
+package sanitize
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	stdunicode "unicode"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/security"
+	"github.com/moba1/usd/unicode"
+	"github.com/moba1/usd/whitespace"
+	"golang.org/x/text/unicode/norm"
+)
+
+// InvisiblePolicy decides what happens to the characters classified by the
+// whitespace package.
+type InvisiblePolicy int
+
+const (
+	KeepInvisible InvisiblePolicy = iota
+	// RemoveInvisible removes format and default ignorable characters and
+	// keeps spaces and line breaks.
+	RemoveInvisible
+	// ReplaceInvisible replaces every invisible character with its suggested
+	// ASCII replacement.
+	ReplaceInvisible
+)
+
+type Normalization int
+
+const (
+	NoNormalization Normalization = iota
+	NFC
+	NFKC
+)
+
+type LineEnding int
+
+const (
+	KeepLineEnding LineEnding = iota
+	LF
+	CRLF
+	CR
+)
+
+// Rules are applied in the order of the fields.
+type Rules struct {
+	// StripBidi removes the characters with the Bidi_Control property.
+	StripBidi bool
+	Invisible InvisiblePolicy
+	// FoldWidth replaces full-width ASCII variants with ASCII.
+	FoldWidth bool
+	// Skeleton replaces non-ASCII confusables with their UTS #39 skeleton.
+	Skeleton      bool
+	Normalization Normalization
+	LineEnding    LineEnding
+}
+
+// Change describes a place where the output differs from the input. Offset
+// is the byte offset in the input.
+type Change struct {
+	Offset int64
+	Input  string
+	Output string
+	Reason string
+}
+
+type Sanitizer struct {
+	Charset *charset.Charset
+	Rules   Rules
+}
+
+// piece is a part of a line with the offset of the input it came from.
+type piece struct {
+	offset int64
+	text   string
+}
+
+// Sanitize streams r to w in s.Charset one line at a time. report is called
+// for every Change and may be nil.
+func (s *Sanitizer) Sanitize(r io.Reader, w io.Writer, report func(Change)) error {
+	if report == nil {
+		report = func(Change) {}
+	}
+	out := bufio.NewWriter(w)
+	err := s.sanitize(r, out, report)
+	// the output sanitized before a failure is kept
+	if flushErr := out.Flush(); err == nil && flushErr != nil {
+		err = fmt.Errorf("can't write output (reason; %s)", flushErr.Error())
+	}
+	return err
+}
+
+func (s *Sanitizer) sanitize(r io.Reader, out *bufio.Writer, report func(Change)) error {
+	scanner := unicode.NewScanner(r, s.Charset.Read)
+	var line []piece
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("can't read input (reason; %s)", err.Error())
+		}
+		if c.Err != nil {
+			return fmt.Errorf("invalid input at offset %d (reason; %s)", c.Offset, c.Err.Error())
+		}
+		line = append(line, piece{offset: c.Offset, text: string(c.Rune)})
+		if c.Rune != '\n' {
+			continue
+		}
+		if err := s.write(out, s.apply(line, report)); err != nil {
+			return err
+		}
+		line = nil
+	}
+	return s.write(out, s.apply(line, report))
+}
+
+func (s *Sanitizer) write(out *bufio.Writer, line []piece) error {
+	for _, p := range line {
+		for _, c := range p.text {
+			bs, err := s.Charset.Encode(c)
+			if err != nil {
+				return fmt.Errorf("can't write output at offset %d (reason; %s)", p.offset, err.Error())
+			}
+			if _, err := out.Write(bs); err != nil {
+				return fmt.Errorf("can't write output (reason; %s)", err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// apply applies the rules to a line and reports the changes in the order of
+// their offsets.
+func (s *Sanitizer) apply(line []piece, report func(Change)) []piece {
+	var changes []Change
+	defer func() {
+		sort.SliceStable(changes, func(i, j int) bool {
+			return changes[i].Offset < changes[j].Offset
+		})
+		for _, c := range changes {
+			report(c)
+		}
+	}()
+	add := func(c Change) {
+		changes = append(changes, c)
+	}
+
+	replace := func(reason string, fn func(p piece) (string, bool)) {
+		var replaced []piece
+		for _, p := range line {
+			text, ok := fn(p)
+			if !ok || text == p.text {
+				replaced = append(replaced, p)
+				continue
+			}
+			add(Change{Offset: p.offset, Input: p.text, Output: text, Reason: reason})
+			if text != "" {
+				replaced = append(replaced, piece{offset: p.offset, text: text})
+			}
+		}
+		line = replaced
+	}
+
+	if s.Rules.StripBidi {
+		replace("bidi control", func(p piece) (string, bool) {
+			return "", stdunicode.Is(stdunicode.Bidi_Control, []rune(p.text)[0])
+		})
+	}
+	if s.Rules.Invisible != KeepInvisible {
+		replace("invisible character", func(p piece) (string, bool) {
+			c := []rune(p.text)[0]
+			if p.offset == 0 && c == 0xFEFF {
+				// the byte order mark is kept
+				return "", false
+			}
+			kind, ok := whitespace.Classify(c)
+			if !ok {
+				return "", false
+			}
+			if s.Rules.Invisible == RemoveInvisible && (kind == whitespace.Space || kind == whitespace.LineBreak) {
+				return "", false
+			}
+			return whitespace.Replacement(kind), true
+		})
+	}
+	if s.Rules.FoldWidth {
+		replace("full-width character", func(p piece) (string, bool) {
+			c := []rune(p.text)[0]
+			if c >= 0xFF01 && c <= 0xFF5E {
+				return string(c - 0xFF01 + '!'), true
+			}
+			return "", false
+		})
+	}
+	if s.Rules.Skeleton {
+		replace("confusable", func(p piece) (string, bool) {
+			if []rune(p.text)[0] < 0x80 {
+				return "", false
+			}
+			skeleton := security.Skeleton(p.text)
+			return skeleton, skeleton != norm.NFD.String(p.text)
+		})
+	}
+	switch s.Rules.Normalization {
+	case NFC:
+		line = normalize(line, norm.NFC, "NFC", add)
+	case NFKC:
+		line = normalize(line, norm.NFKC, "NFKC", add)
+	}
+	if s.Rules.LineEnding != KeepLineEnding {
+		line = convertLineEnding(line, s.Rules.LineEnding, add)
+	}
+	return line
+}
+
+// normalize joins the pieces of each normalization segment and normalizes
+// them. A change is reported at the beginning of the segment.
+func normalize(line []piece, form norm.Form, reason string, report func(Change)) []piece {
+	var normalized []piece
+	for i := 0; i < len(line); {
+		segment := line[i]
+		for i++; i < len(line) && !form.PropertiesString(line[i].text).BoundaryBefore(); i++ {
+			segment.text += line[i].text
+		}
+		text := form.String(segment.text)
+		if text != segment.text {
+			report(Change{Offset: segment.offset, Input: segment.text, Output: text, Reason: reason})
+		}
+		normalized = append(normalized, piece{offset: segment.offset, text: text})
+	}
+	return normalized
+}
+
+func convertLineEnding(line []piece, lineEnding LineEnding, report func(Change)) []piece {
+	var newLine string
+	switch lineEnding {
+	case LF:
+		newLine = "\n"
+	case CRLF:
+		newLine = "\r\n"
+	case CR:
+		newLine = "\r"
+	}
+	var converted []piece
+	for i := 0; i < len(line); i++ {
+		p := line[i]
+		if p.text != "\r" && p.text != "\n" {
+			converted = append(converted, p)
+			continue
+		}
+		if p.text == "\r" && i+1 < len(line) && line[i+1].text == "\n" {
+			p.text = "\r\n"
+			i++
+		}
+		if p.text != newLine {
+			report(Change{Offset: p.offset, Input: p.text, Output: newLine, Reason: "line ending"})
+		}
+		converted = append(converted, piece{offset: p.offset, text: newLine})
+	}
+	return converted
+}
@@ -0,0 +1,142 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/sanitize"
+	"github.com/moba1/usd/unicode"
+)
+
+func lookup(t *testing.T, name string, endian unicode.Endian) *charset.Charset {
+	c, err := charset.Lookup(name, endian)
+	if err != nil {
+		t.Fatalf("charset.Lookup(%q) returns error: %v", name, err)
+	}
+	return c
+}
+
+func TestSanitizer_Sanitize(t *testing.T) {
+	utf8 := lookup(t, "UTF8", unicode.BigEndian)
+	testCases := []struct {
+		title   string
+		rules   sanitize.Rules
+		input   string
+		output  string
+		changes []sanitize.Change
+	}{
+		{
+			title:  "strip bidi controls",
+			rules:  sanitize.Rules{StripBidi: true},
+			input:  "a\u202Eb\u202C\u200Ec",
+			output: "abc",
+			changes: []sanitize.Change{
+				{Offset: 1, Input: "\u202E", Reason: "bidi control"},
+				{Offset: 5, Input: "\u202C", Reason: "bidi control"},
+				{Offset: 8, Input: "\u200E", Reason: "bidi control"},
+			},
+		},
+		{
+			title:  "remove invisible characters",
+			rules:  sanitize.Rules{Invisible: sanitize.RemoveInvisible},
+			input:  "\uFEFFa\u200Bb c",
+			output: "\uFEFFab c",
+			changes: []sanitize.Change{
+				{Offset: 4, Input: "\u200B", Reason: "invisible character"},
+			},
+		},
+		{
+			title:  "replace invisible characters",
+			rules:  sanitize.Rules{Invisible: sanitize.ReplaceInvisible},
+			input:  "a\u00ADb\u3000c",
+			output: "ab c",
+			changes: []sanitize.Change{
+				{Offset: 1, Input: "\u00AD", Reason: "invisible character"},
+				{Offset: 4, Input: "\u3000", Output: " ", Reason: "invisible character"},
+			},
+		},
+		{
+			title:  "fold full-width ASCII",
+			rules:  sanitize.Rules{FoldWidth: true},
+			input:  "Ａ１ア",
+			output: "A1ア",
+			changes: []sanitize.Change{
+				{Offset: 0, Input: "Ａ", Output: "A", Reason: "full-width character"},
+				{Offset: 3, Input: "１", Output: "1", Reason: "full-width character"},
+			},
+		},
+		{
+			title:  "replace confusables",
+			rules:  sanitize.Rules{Skeleton: true, Normalization: sanitize.NFC},
+			input:  "раураl \u00E9",
+			output: "paypal \u00E9",
+			changes: []sanitize.Change{
+				{Offset: 0, Input: "р", Output: "p", Reason: "confusable"},
+				{Offset: 2, Input: "а", Output: "a", Reason: "confusable"},
+				{Offset: 4, Input: "у", Output: "y", Reason: "confusable"},
+				{Offset: 6, Input: "р", Output: "p", Reason: "confusable"},
+				{Offset: 8, Input: "а", Output: "a", Reason: "confusable"},
+			},
+		},
+		{
+			title:  "normalize to NFKC",
+			rules:  sanitize.Rules{Normalization: sanitize.NFKC},
+			input:  "e\u0301\uFB01",
+			output: "\u00E9fi",
+			changes: []sanitize.Change{
+				{Offset: 0, Input: "e\u0301", Output: "\u00E9", Reason: "NFKC"},
+				{Offset: 3, Input: "\uFB01", Output: "fi", Reason: "NFKC"},
+			},
+		},
+		{
+			title:  "convert line endings",
+			rules:  sanitize.Rules{LineEnding: sanitize.LF},
+			input:  "a\r\nb\rc\n",
+			output: "a\nb\nc\n",
+			changes: []sanitize.Change{
+				{Offset: 1, Input: "\r\n", Output: "\n", Reason: "line ending"},
+				{Offset: 4, Input: "\r", Output: "\n", Reason: "line ending"},
+			},
+		},
+	}
+	for _, c := range testCases {
+		var output bytes.Buffer
+		var changes []sanitize.Change
+		sanitizer := sanitize.Sanitizer{Charset: utf8, Rules: c.rules}
+		err := sanitizer.Sanitize(bytes.NewBufferString(c.input), &output, func(change sanitize.Change) {
+			changes = append(changes, change)
+		})
+		if err != nil {
+			t.Errorf("%s: Sanitizer.Sanitize returns error: %v", c.title, err)
+			continue
+		}
+		if output.String() != c.output {
+			t.Errorf("%s: Sanitizer.Sanitize writes %q, but expected value is %q", c.title, output.String(), c.output)
+		}
+		if !reflect.DeepEqual(changes, c.changes) {
+			t.Errorf("%s: Sanitizer.Sanitize reports %+v, but expected value is %+v", c.title, changes, c.changes)
+		}
+	}
+}
+
+func TestSanitizer_Sanitize_InputEncoding(t *testing.T) {
+	sanitizer := sanitize.Sanitizer{
+		Charset: lookup(t, "UTF16", unicode.LittleEndian),
+		Rules:   sanitize.Rules{Invisible: sanitize.ReplaceInvisible},
+	}
+	var output bytes.Buffer
+	input := []byte{0xFF, 0xFE, 0x61, 0x00, 0xA0, 0x00, 0x62, 0x00}
+	if err := sanitizer.Sanitize(bytes.NewBuffer(input), &output, nil); err != nil {
+		t.Fatalf("Sanitizer.Sanitize returns error: %v", err)
+	}
+	expected := []byte{0xFF, 0xFE, 0x61, 0x00, 0x20, 0x00, 0x62, 0x00}
+	if !bytes.Equal(output.Bytes(), expected) {
+		t.Errorf("Sanitizer.Sanitize writes %v, but expected value is %v", output.Bytes(), expected)
+	}
+
+	if err := sanitizer.Sanitize(bytes.NewBuffer([]byte{0x61}), &output, nil); err == nil {
+		t.Errorf("Sanitizer.Sanitize accepts invalid input")
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/search"
+	"github.com/moba1/usd/ucd"
+)
+
+const searchCmdName = "search"
+
+func parseSearchCmd(args []string) func() error {
+	searchCmd := flag.NewFlagSet(searchCmdName, flag.ExitOnError)
+	var (
+		mode       = search.Substring
+		block      string
+		script     string
+		categories []string
+	)
+	searchCmd.Func("mode", "matching `mode`. default is 'Substring' (value: Substring|Regex|Fuzzy)", func(s string) error {
+		switch s {
+		case "Substring":
+			mode = search.Substring
+		case "Regex":
+			mode = search.Regex
+		case "Fuzzy":
+			mode = search.Fuzzy
+		default:
+			return fmt.Errorf("invalid mode: %s", s)
+		}
+		return nil
+	})
+	searchCmd.StringVar(&block, "block", "", "only characters in `block`")
+	searchCmd.StringVar(&script, "script", "", "only characters of `script`")
+	searchCmd.Func("category", "only characters of comma separated general `categories` (e.g. Lu,Nd or L)", func(s string) error {
+		categories = nil
+		for _, name := range strings.Split(s, ",") {
+			category, err := ucd.LookupCategory(strings.TrimSpace(name))
+			if err != nil {
+				return fmt.Errorf("can't parse category (reason; %s)", err.Error())
+			}
+			categories = append(categories, category)
+		}
+		return nil
+	})
+	searchCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", searchCmdName),
+			fmt.Sprintf("  %s [option] <pattern>", searchCmdName),
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(searchCmd.Output(), stmt)
+		}
+		searchCmd.PrintDefaults()
+	}
+	if err := searchCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+	if searchCmd.NArg() != 1 {
+		searchCmd.Usage()
+		os.Exit(2)
+	}
+	pattern := searchCmd.Arg(0)
+
+	return func() error {
+		m, err := search.NewMatcher(mode, pattern)
+		if err != nil {
+			return err
+		}
+		var filters []func(rune) bool
+		if block != "" {
+			b, err := ucd.LookupBlock(block)
+			if err != nil {
+				return err
+			}
+			filters = append(filters, func(c rune) bool {
+				return b.First <= c && c <= b.Last
+			})
+		}
+		if script != "" {
+			s, err := ucd.LookupScript(script)
+			if err != nil {
+				return err
+			}
+			filters = append(filters, func(c rune) bool {
+				return ucd.Script(c) == s
+			})
+		}
+		if len(categories) > 0 {
+			filters = append(filters, func(c rune) bool {
+				for _, category := range categories {
+					if ucd.MatchCategory(c, category) {
+						return true
+					}
+				}
+				return false
+			})
+		}
+
+		resultTable := newTableEncoder(tableOutput)
+		if !noHeader {
+			resultTable.SetHeader([]string{"Character", "Code Point", "Name", "Alias", "Block", "Script", "Category"})
+		}
+		results := search.Search(m, func(c rune) bool {
+			for _, filter := range filters {
+				if !filter(c) {
+					return false
+				}
+			}
+			return true
+		})
+		for _, r := range results {
+			b, _ := ucd.BlockOf(r.Char)
+			resultTable.Append([]string{
+				encoder.GraphicString(r.Char),
+				fmt.Sprintf("%U", r.Char),
+				r.Name,
+				r.Alias,
+				b.Name,
+				ucd.Script(r.Char),
+				ucd.Category(r.Char),
+			})
+		}
+		return resultTable.Render()
+	}
+}
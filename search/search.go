@@ -0,0 +1,185 @@
+// Package search finds characters by their names and aliases.
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moba1/usd/ucd"
+)
+
+type Mode int
+
+const (
+	Substring Mode = iota
+	Regex
+	Fuzzy
+)
+
+// Matcher scores a character name. Lower scores are better matches.
+type Matcher interface {
+	Match(name string) (score int, ok bool)
+}
+
+type substringMatcher struct {
+	pattern string
+}
+
+func (m *substringMatcher) Match(name string) (int, bool) {
+	return 0, strings.Contains(name, m.pattern)
+}
+
+type regexMatcher struct {
+	pattern *regexp.Regexp
+}
+
+func (m *regexMatcher) Match(name string) (int, bool) {
+	return 0, m.pattern.MatchString(name)
+}
+
+// fuzzyMatcher matches names which have a similar word for every term of the
+// pattern, in any order. "ARROW ... DOUBLE" finds "DOUBLE ARROW LEFT".
+type fuzzyMatcher struct {
+	terms []string
+}
+
+func (m *fuzzyMatcher) Match(name string) (int, bool) {
+	words := strings.FieldsFunc(name, func(c rune) bool {
+		return c == ' ' || c == '-'
+	})
+	score := 0
+	for _, term := range m.terms {
+		best := -1
+		for _, word := range words {
+			d := wordDistance(term, word)
+			if d >= 0 && (best < 0 || d < best) {
+				best = d
+			}
+		}
+		if best < 0 {
+			return 0, false
+		}
+		score += best
+	}
+	return score, true
+}
+
+// wordDistance is 0 when term is word, 1 when term is a prefix of word,
+// otherwise the edit distance plus one if it is small enough for the length
+// of term, or -1.
+func wordDistance(term, word string) int {
+	if term == word {
+		return 0
+	}
+	if strings.HasPrefix(word, term) {
+		return 1
+	}
+	maxDistance := len(term) / 4
+	if maxDistance == 0 || len(word) < len(term)-maxDistance || len(word) > len(term)+maxDistance {
+		return -1
+	}
+	if d := editDistance(term, word); d <= maxDistance {
+		return d + 1
+	}
+	return -1
+}
+
+// editDistance is the optimal string alignment distance, so that swapped
+// letters count as one typo.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = d[i-1][j-1] + cost
+			if d[i-1][j]+1 < d[i][j] {
+				d[i][j] = d[i-1][j] + 1
+			}
+			if d[i][j-1]+1 < d[i][j] {
+				d[i][j] = d[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+// NewMatcher returns a case insensitive Matcher.
+func NewMatcher(mode Mode, pattern string) (Matcher, error) {
+	switch mode {
+	case Substring:
+		return &substringMatcher{pattern: strings.ToUpper(pattern)}, nil
+	case Regex:
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression (reason; %s)", err.Error())
+		}
+		return &regexMatcher{pattern: re}, nil
+	case Fuzzy:
+		var terms []string
+		for _, term := range strings.Fields(strings.ToUpper(pattern)) {
+			if strings.Trim(term, ".") != "" {
+				terms = append(terms, term)
+			}
+		}
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("empty pattern")
+		}
+		return &fuzzyMatcher{terms: terms}, nil
+	}
+	return nil, fmt.Errorf("unknown mode: %d", mode)
+}
+
+type Result struct {
+	Char rune
+	Name string
+	// Alias is the alias which matched, when the name itself didn't.
+	Alias string
+	score int
+}
+
+// Search returns the characters whose name or alias matches m. Characters
+// for which accept returns false are skipped; accept may be nil. Results are
+// in code point order, except that better fuzzy matches come first.
+func Search(m Matcher, accept func(rune) bool) []Result {
+	var results []Result
+	for c := rune(0); c <= 0x10FFFF; c++ {
+		if accept != nil && !accept(c) {
+			continue
+		}
+		name := ucd.Name(c)
+		result := Result{Char: c, Name: name}
+		score, ok := 0, false
+		if name != "" {
+			score, ok = m.Match(name)
+		}
+		for _, alias := range ucd.Aliases(c) {
+			if aliasScore, aliasOk := m.Match(alias.Name); aliasOk && (!ok || aliasScore < score) {
+				score, ok = aliasScore, true
+				result.Alias = alias.Name
+			}
+		}
+		if ok {
+			result.score = score
+			results = append(results, result)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score < results[j].score
+	})
+	return results
+}
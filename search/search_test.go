@@ -0,0 +1,95 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/moba1/usd/search"
+)
+
+func contains(results []search.Result, c rune) bool {
+	for _, r := range results {
+		if r.Char == c {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSearch_Substring(t *testing.T) {
+	m, err := search.NewMatcher(search.Substring, "penguin")
+	if err != nil {
+		t.Fatalf("search.NewMatcher returns error: %v", err)
+	}
+	results := search.Search(m, nil)
+	if len(results) != 1 || results[0].Char != '🐧' || results[0].Name != "PENGUIN" {
+		t.Errorf("search.Search returns %v, but expected value is PENGUIN only", results)
+	}
+}
+
+func TestSearch_Alias(t *testing.T) {
+	m, err := search.NewMatcher(search.Substring, "BYTE ORDER MARK")
+	if err != nil {
+		t.Fatalf("search.NewMatcher returns error: %v", err)
+	}
+	results := search.Search(m, nil)
+	if len(results) != 1 || results[0].Char != 0xFEFF || results[0].Alias != "BYTE ORDER MARK" {
+		t.Errorf("search.Search returns %v, but expected value is U+FEFF found by alias", results)
+	}
+}
+
+func TestSearch_Regex(t *testing.T) {
+	m, err := search.NewMatcher(search.Regex, `^hiragana letter small \w+$`)
+	if err != nil {
+		t.Fatalf("search.NewMatcher returns error: %v", err)
+	}
+	results := search.Search(m, func(c rune) bool {
+		return c < 0x3100
+	})
+	if !contains(results, 'ぁ') || contains(results, 'あ') {
+		t.Errorf("search.Search returns %v", results)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i-1].Char > results[i].Char {
+			t.Errorf("search.Search returns results out of code point order: %v", results)
+		}
+	}
+
+	if _, err := search.NewMatcher(search.Regex, "("); err == nil {
+		t.Errorf("search.NewMatcher accepts invalid regular expression")
+	}
+}
+
+func TestSearch_Fuzzy(t *testing.T) {
+	m, err := search.NewMatcher(search.Fuzzy, "arrow ... double")
+	if err != nil {
+		t.Fatalf("search.NewMatcher returns error: %v", err)
+	}
+	results := search.Search(m, nil)
+	// LEFTWARDS DOUBLE ARROW
+	if !contains(results, 0x21D0) {
+		t.Errorf("search.Search doesn't find LEFTWARDS DOUBLE ARROW")
+	}
+
+	// typo
+	m, err = search.NewMatcher(search.Fuzzy, "pengiun")
+	if err != nil {
+		t.Fatalf("search.NewMatcher returns error: %v", err)
+	}
+	if results := search.Search(m, nil); !contains(results, '🐧') {
+		t.Errorf("search.Search doesn't find PENGUIN with typo")
+	}
+
+	// exact prefixes come before typos
+	m, err = search.NewMatcher(search.Fuzzy, "bath")
+	if err != nil {
+		t.Fatalf("search.NewMatcher returns error: %v", err)
+	}
+	results = search.Search(m, nil)
+	if len(results) == 0 || results[0].Char != 0x1F6C0 {
+		t.Errorf("search.Search doesn't find BATH first: %v", results)
+	}
+
+	if _, err := search.NewMatcher(search.Fuzzy, " ... "); err == nil {
+		t.Errorf("search.NewMatcher accepts empty pattern")
+	}
+}
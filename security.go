@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/security"
+	"github.com/moba1/usd/ucd"
+	"github.com/moba1/usd/unicode"
+	"golang.org/x/text/unicode/runenames"
+)
+
+const securityCmdName = "security"
+
+func parseSecurityCmd(args []string) func() error {
+	securityCmd := flag.NewFlagSet(securityCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(securityCmd)
+	var characters bool
+	securityCmd.BoolVar(&characters, "characters", false, "show each character instead of each identifier")
+	securityCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", securityCmdName),
+			fmt.Sprintf("  %s [option]", securityCmdName),
+			"Each line of the input is checked as an identifier.",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(securityCmd.Output(), stmt)
+		}
+		securityCmd.PrintDefaults()
+	}
+	if err := securityCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		table := newTableEncoder(tableOutput)
+		if !noHeader {
+			if characters {
+				table.SetHeader([]string{"Character", "Code Point", "Name", "Script Extensions", "Identifier Status", "Identifier Type", "Skeleton"})
+			} else {
+				table.SetHeader([]string{"Identifier", "Skeleton", "Scripts", "Mixed Script", "Whole-Script Confusable", "Restricted"})
+			}
+		}
+		err = scanLines(os.Stdin, cs, func(line []unicode.Char) error {
+			var b strings.Builder
+			for _, c := range line {
+				if c.Err != nil {
+					return fmt.Errorf("line %d, column %d (reason; %s)", c.Line, c.Column, c.Err.Error())
+				}
+				b.WriteRune(c.Rune)
+			}
+			identifier := b.String()
+			if identifier == "" {
+				return nil
+			}
+
+			if characters {
+				for _, c := range identifier {
+					table.Append([]string{
+						encoder.GraphicString(c),
+						fmt.Sprintf("%U", c),
+						runenames.Name(c),
+						strings.Join(ucd.ScriptExtensions(c), " "),
+						security.IdentifierStatus(c),
+						strings.Join(security.IdentifierTypes(c), " "),
+						security.Skeleton(string(c)),
+					})
+				}
+				return nil
+			}
+
+			var restricted []string
+			for _, c := range identifier {
+				if security.IdentifierStatus(c) == "Restricted" {
+					restricted = append(restricted, fmt.Sprintf("%U %s", c, strings.Join(security.IdentifierTypes(c), " ")))
+				}
+			}
+			scripts, all := security.ResolvedScripts(identifier)
+			resolved := strings.Join(scripts, " ")
+			if all {
+				resolved = "ALL"
+			}
+			mixed := "no"
+			if security.IsMixedScript(identifier) {
+				mixed = "yes"
+			}
+			table.Append([]string{
+				identifier,
+				security.Skeleton(identifier),
+				resolved,
+				mixed,
+				strings.Join(security.WholeScriptConfusables(identifier), " "),
+				strings.Join(restricted, ", "),
+			})
+			return nil
+		})
+		if renderErr := table.Render(); err == nil {
+			err = renderErr
+		}
+		return err
+	}
+}
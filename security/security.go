@@ -0,0 +1,201 @@
+// Package security implements the identifier checks of UTS #39, Unicode
+// Security Mechanisms.
+package security
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/moba1/usd/ucd"
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed data/IdentifierStatus.txt
+var identifierStatusTxt string
+
+//go:embed data/IdentifierType.txt
+var identifierTypeTxt string
+
+//go:embed data/confusables.txt
+var confusablesTxt string
+
+var (
+	loadOnce        sync.Once
+	statuses        ucd.RangeTable
+	types           ucd.RangeTable
+	confusables     map[rune]string
+	skeletonScripts map[string]map[string]bool
+)
+
+func load() {
+	loadOnce.Do(func() {
+		statuses = ucd.ParseRangeTable(identifierStatusTxt)
+		types = ucd.ParseRangeTable(identifierTypeTxt)
+		confusables = map[rune]string{}
+		ucd.ParseFields(confusablesTxt, func(fields []string) {
+			confusables[ucd.ParseDataCodePoints(fields[0])[0]] = string(ucd.ParseDataCodePoints(fields[1]))
+		})
+
+		// scripts of the characters which look like each skeleton, for
+		// whole-script confusable detection
+		skeletonScripts = map[string]map[string]bool{}
+		add := func(c rune) {
+			key := skeleton(string(c))
+			for _, script := range ucd.ScriptExtensions(c) {
+				if script == "Common" || script == "Inherited" {
+					continue
+				}
+				if skeletonScripts[key] == nil {
+					skeletonScripts[key] = map[string]bool{}
+				}
+				skeletonScripts[key][script] = true
+			}
+		}
+		for source, target := range confusables {
+			add(source)
+			if rs := []rune(target); len(rs) == 1 {
+				add(rs[0])
+			}
+		}
+	})
+}
+
+// IdentifierStatus returns "Allowed" or "Restricted".
+func IdentifierStatus(c rune) string {
+	load()
+	if s, ok := statuses.Lookup(c); ok {
+		return s
+	}
+	return "Restricted"
+}
+
+// IdentifierTypes returns the Identifier_Type values of c, such as
+// "Recommended" or "Not_XID".
+func IdentifierTypes(c rune) []string {
+	load()
+	if s, ok := types.Lookup(c); ok {
+		return strings.Fields(s)
+	}
+	return []string{"Not_Character"}
+}
+
+// Skeleton is the string that all strings confusable with s share.
+func Skeleton(s string) string {
+	load()
+	return skeleton(s)
+}
+
+func skeleton(s string) string {
+	var b strings.Builder
+	for _, c := range norm.NFD.String(s) {
+		if target, ok := confusables[c]; ok {
+			b.WriteString(target)
+		} else {
+			b.WriteRune(c)
+		}
+	}
+	return norm.NFD.String(b.String())
+}
+
+// augmented script sets let Han mix with Japanese kana and with Hangul
+var augmentations = map[string][]string{
+	"Han":      {"Han_with_Bopomofo", "Japanese", "Korean"},
+	"Hiragana": {"Japanese"},
+	"Katakana": {"Japanese"},
+	"Hangul":   {"Korean"},
+	"Bopomofo": {"Han_with_Bopomofo"},
+}
+
+func augmentedScripts(c rune) map[string]bool {
+	scripts := map[string]bool{}
+	for _, script := range ucd.ScriptExtensions(c) {
+		scripts[script] = true
+		for _, a := range augmentations[script] {
+			scripts[a] = true
+		}
+	}
+	return scripts
+}
+
+func isAnyScript(scripts map[string]bool) bool {
+	return scripts["Common"] || scripts["Inherited"]
+}
+
+// ResolvedScripts returns the resolved script set of s. all is true when s
+// consists only of Common and Inherited characters, which fit any script.
+func ResolvedScripts(s string) (scripts []string, all bool) {
+	var resolved map[string]bool
+	for _, c := range s {
+		augmented := augmentedScripts(c)
+		if isAnyScript(augmented) {
+			continue
+		}
+		if resolved == nil {
+			resolved = augmented
+			continue
+		}
+		for script := range resolved {
+			if !augmented[script] {
+				delete(resolved, script)
+			}
+		}
+	}
+	if resolved == nil {
+		return nil, true
+	}
+	for script := range resolved {
+		scripts = append(scripts, script)
+	}
+	sort.Strings(scripts)
+	return scripts, false
+}
+
+// IsMixedScript reports whether no single script covers s.
+func IsMixedScript(s string) bool {
+	scripts, all := ResolvedScripts(s)
+	return !all && len(scripts) == 0
+}
+
+// WholeScriptConfusables returns the scripts in which a string confusable
+// with s can be written entirely, other than the scripts of s itself. It is
+// empty for mixed-script strings.
+func WholeScriptConfusables(s string) []string {
+	load()
+	own, all := ResolvedScripts(s)
+	if all || len(own) == 0 {
+		return nil
+	}
+	var candidates map[string]bool
+	for _, c := range norm.NFD.String(s) {
+		if isAnyScript(augmentedScripts(c)) {
+			continue
+		}
+		scripts := map[string]bool{}
+		for script := range skeletonScripts[Skeleton(string(c))] {
+			scripts[script] = true
+		}
+		for _, script := range ucd.ScriptExtensions(c) {
+			scripts[script] = true
+		}
+		if candidates == nil {
+			candidates = scripts
+			continue
+		}
+		for script := range candidates {
+			if !scripts[script] {
+				delete(candidates, script)
+			}
+		}
+	}
+	for _, script := range own {
+		delete(candidates, script)
+	}
+	var result []string
+	for script := range candidates {
+		result = append(result, script)
+	}
+	sort.Strings(result)
+	return result
+}
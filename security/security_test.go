@@ -0,0 +1,103 @@
+package security_test
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/security"
+)
+
+func TestIdentifierStatus(t *testing.T) {
+	testCases := map[rune]string{
+		'a':    "Allowed",
+		'あ':    "Allowed",
+		0x0430: "Allowed",
+		0x200B: "Restricted",
+		0x2126: "Restricted",
+	}
+	for c, expected := range testCases {
+		if s := security.IdentifierStatus(c); s != expected {
+			t.Errorf("security.IdentifierStatus(%U) returns %q, but expected value is %q", c, s, expected)
+		}
+	}
+}
+
+func TestIdentifierTypes(t *testing.T) {
+	testCases := map[rune][]string{
+		'a':      {"Recommended"},
+		' ':      {"Not_XID"},
+		0x2126:   {"Not_NFKC"},
+		0x0378:   {"Not_Character"},
+		0x10FFFF: {"Not_Character"},
+	}
+	for c, expected := range testCases {
+		if types := security.IdentifierTypes(c); !reflect.DeepEqual(types, expected) {
+			t.Errorf("security.IdentifierTypes(%U) returns %v, but expected value is %v", c, types, expected)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	if security.Skeleton("pаypal") != security.Skeleton("paypal") {
+		t.Errorf("skeleton of Cyrillic a differs from Latin a")
+	}
+	if security.Skeleton("ℌello") != security.Skeleton("Hello") {
+		t.Errorf("skeleton of U+210C differs from Latin H")
+	}
+	if security.Skeleton("rn") != security.Skeleton("m") {
+		t.Errorf("skeleton of rn differs from m")
+	}
+}
+
+func TestResolvedScripts(t *testing.T) {
+	scripts, all := security.ResolvedScripts("漢字かな")
+	if all || !reflect.DeepEqual(scripts, []string{"Japanese"}) {
+		t.Errorf("security.ResolvedScripts returns (%v, %v), but expected value is [Japanese]", scripts, all)
+	}
+	if _, all := security.ResolvedScripts("123_"); !all {
+		t.Errorf("Common characters don't fit any script")
+	}
+	if !security.IsMixedScript("pаypal") {
+		t.Errorf("Latin with Cyrillic a isn't mixed-script")
+	}
+	if security.IsMixedScript("paypal") || security.IsMixedScript("ーカー") {
+		t.Errorf("single script is mixed-script")
+	}
+}
+
+func TestWholeScriptConfusables(t *testing.T) {
+	// Cyrillic letters
+	if scripts := security.WholeScriptConfusables("соре"); !reflect.DeepEqual(scripts, []string{"Latin"}) {
+		t.Errorf("security.WholeScriptConfusables returns %v, but expected value is [Latin]", scripts)
+	}
+	if scripts := security.WholeScriptConfusables("golang"); len(scripts) != 0 {
+		t.Errorf("security.WholeScriptConfusables returns %v, but expected value is empty", scripts)
+	}
+	if scripts := security.WholeScriptConfusables("pаypal"); scripts != nil {
+		t.Errorf("security.WholeScriptConfusables returns %v for mixed-script", scripts)
+	}
+}
+
+func TestDataVersion(t *testing.T) {
+	fileNames, err := filepath.Glob(filepath.Join("data", "*.txt"))
+	if err != nil || len(fileNames) == 0 {
+		t.Fatalf("can't find data files (reason; %v)", err)
+	}
+	for _, fileName := range fileNames {
+		f, err := os.Open(fileName)
+		if err != nil {
+			t.Fatalf("can't open %s (reason; %v)", fileName, err)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Scan()
+		f.Close()
+		name := filepath.Base(fileName)
+		expected := "# " + name[:len(name)-len(".txt")] + "-14.0.0.txt"
+		if header := scanner.Text(); header != expected {
+			t.Errorf("the header of %s is %q, but expected value is %q", fileName, header, expected)
+		}
+	}
+}
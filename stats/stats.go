@@ -0,0 +1,210 @@
+// Package stats summarizes the characters of an input.
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/moba1/usd/grapheme"
+	"github.com/moba1/usd/ucd"
+	"github.com/moba1/usd/unicode"
+)
+
+// Count is the number of occurrences of Key.
+type Count struct {
+	Key   string
+	Count int64
+}
+
+// CharCount is the number of occurrences of Char.
+type CharCount struct {
+	Char  rune
+	Count int64
+}
+
+// Stats counts the characters given to Add.
+type Stats struct {
+	Bytes      int64
+	CodePoints int64
+	Graphemes  int64
+	Lines      int64
+	// BOM is true when the input starts with U+FEFF.
+	BOM         bool
+	Planes      map[string]int64
+	Blocks      map[string]int64
+	Scripts     map[string]int64
+	Categories  map[string]int64
+	Errors      map[string]int64
+	LineEndings map[string]int64
+	chars       map[rune]int64
+	splitter    grapheme.Splitter
+	// cr is true after CR, which is a line ending unless LF follows it.
+	cr bool
+	// lineOpen is true when characters follow the last line ending.
+	lineOpen bool
+}
+
+func New() *Stats {
+	return &Stats{
+		Planes:      map[string]int64{},
+		Blocks:      map[string]int64{},
+		Scripts:     map[string]int64{},
+		Categories:  map[string]int64{},
+		Errors:      map[string]int64{},
+		LineEndings: map[string]int64{},
+		chars:       map[rune]int64{},
+	}
+}
+
+var planeNames = map[rune]string{
+	0:  "BMP",
+	1:  "SMP",
+	2:  "SIP",
+	3:  "TIP",
+	14: "SSP",
+	15: "SPUA-A",
+	16: "SPUA-B",
+}
+
+// PlaneName returns the plane of c such as "0 (BMP)".
+func PlaneName(c rune) string {
+	plane := c >> 16
+	if name, ok := planeNames[plane]; ok {
+		return fmt.Sprintf("%d (%s)", plane, name)
+	}
+	return fmt.Sprintf("%d", plane)
+}
+
+func errorName(err error) string {
+	var invalidSequenceErr *unicode.InvalidSequenceErr
+	var unexpectedEofErr *unicode.UnexpectedEofErr
+	switch {
+	case errors.As(err, &invalidSequenceErr):
+		return "Invalid Sequence"
+	case errors.As(err, &unexpectedEofErr):
+		return "Unexpected EOF"
+	}
+	return err.Error()
+}
+
+func (s *Stats) endLine(lineEnding string) {
+	s.LineEndings[lineEnding]++
+	s.Lines++
+	s.lineOpen = false
+}
+
+// Add counts the next character of the input.
+func (s *Stats) Add(c unicode.Char) {
+	s.Bytes += int64(len(c.Bytes))
+	afterCR := s.cr
+	s.cr = false
+	if afterCR && !(c.Err == nil && c.Rune == '\n') {
+		s.endLine("CR")
+	}
+	if s.splitter.IsBoundary(c.Rune) {
+		s.Graphemes++
+	}
+	if c.Err != nil {
+		s.Errors[errorName(c.Err)]++
+		s.lineOpen = true
+		return
+	}
+
+	s.CodePoints++
+	if c.Offset == 0 && c.Rune == 0xFEFF {
+		s.BOM = true
+	}
+	s.Planes[PlaneName(c.Rune)]++
+	if block, ok := ucd.BlockOf(c.Rune); ok {
+		s.Blocks[block.Name]++
+	} else {
+		s.Blocks["No_Block"]++
+	}
+	s.Scripts[ucd.Script(c.Rune)]++
+	s.Categories[ucd.Category(c.Rune)]++
+	s.chars[c.Rune]++
+
+	switch c.Rune {
+	case '\r':
+		s.cr = true
+	case '\n':
+		if afterCR {
+			s.endLine("CRLF")
+		} else {
+			s.endLine("LF")
+		}
+	case 0x0085:
+		s.endLine("NEL")
+	case 0x2028:
+		s.endLine("LS")
+	case 0x2029:
+		s.endLine("PS")
+	default:
+		s.lineOpen = true
+	}
+}
+
+// Finish counts the last line. It must be called after the last Add.
+func (s *Stats) Finish() {
+	if s.cr {
+		s.cr = false
+		s.endLine("CR")
+	}
+	if s.lineOpen {
+		s.Lines++
+		s.lineOpen = false
+	}
+}
+
+// LineEndingStyle returns the only line ending of the input, "Mixed" or
+// "None".
+func (s *Stats) LineEndingStyle() string {
+	switch len(s.LineEndings) {
+	case 0:
+		return "None"
+	case 1:
+		for lineEnding := range s.LineEndings {
+			return lineEnding
+		}
+	}
+	return "Mixed"
+}
+
+// Sorted returns counts in descending order of Count, and in ascending order
+// of Key for the same Count.
+func Sorted(counts map[string]int64) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for key, count := range counts {
+		sorted = append(sorted, Count{Key: key, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+// Top returns the n most frequent characters. Characters with the same count
+// are in code point order. A negative n is 0.
+func (s *Stats) Top(n int) []CharCount {
+	if n < 0 {
+		n = 0
+	}
+	top := make([]CharCount, 0, len(s.chars))
+	for c, count := range s.chars {
+		top = append(top, CharCount{Char: c, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Char < top[j].Char
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
@@ -0,0 +1,83 @@
+package stats_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/stats"
+	"github.com/moba1/usd/unicode"
+)
+
+func collect(t *testing.T, input []byte) *stats.Stats {
+	s := stats.New()
+	scanner := unicode.NewScanner(bytes.NewBuffer(input), unicode.ReadUtf8Char)
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Scanner.Scan returns error: %v", err)
+		}
+		s.Add(c)
+	}
+	s.Finish()
+	return s
+}
+
+func TestStats(t *testing.T) {
+	s := collect(t, []byte("\uFEFFa\u00E9\r\n\u3042a\xFE\n\U0001F427"))
+	if s.Bytes != 18 || s.CodePoints != 9 || s.Graphemes != 9 || s.Lines != 3 || !s.BOM {
+		t.Errorf("stats are %d bytes, %d code points, %d graphemes, %d lines and BOM %v", s.Bytes, s.CodePoints, s.Graphemes, s.Lines, s.BOM)
+	}
+	expectedPlanes := map[string]int64{"0 (BMP)": 8, "1 (SMP)": 1}
+	if !reflect.DeepEqual(s.Planes, expectedPlanes) {
+		t.Errorf("planes are %v, but expected value is %v", s.Planes, expectedPlanes)
+	}
+	if s.Scripts["Latin"] != 3 || s.Scripts["Hiragana"] != 1 || s.Blocks["Miscellaneous Symbols and Pictographs"] != 1 || s.Categories["Ll"] != 3 {
+		t.Errorf("scripts are %v, blocks are %v and categories are %v", s.Scripts, s.Blocks, s.Categories)
+	}
+	expectedErrors := map[string]int64{"Invalid Sequence": 1}
+	if !reflect.DeepEqual(s.Errors, expectedErrors) {
+		t.Errorf("errors are %v, but expected value is %v", s.Errors, expectedErrors)
+	}
+	if style := s.LineEndingStyle(); style != "Mixed" {
+		t.Errorf("line ending style is %s, but expected value is Mixed", style)
+	}
+	expectedTop := []stats.CharCount{{Char: '\n', Count: 2}, {Char: 'a', Count: 2}}
+	if top := s.Top(2); !reflect.DeepEqual(top, expectedTop) {
+		t.Errorf("Stats.Top returns %v, but expected value is %v", top, expectedTop)
+	}
+	if top := s.Top(-1); len(top) != 0 {
+		t.Errorf("Stats.Top(-1) returns %v, but expected value is empty", top)
+	}
+}
+
+func TestStats_LineEndings(t *testing.T) {
+	testCases := []struct {
+		input string
+		lines int64
+		style string
+	}{
+		{"", 0, "None"},
+		{"a", 1, "None"},
+		{"a\r\nb\r\n", 2, "CRLF"},
+		{"a\rb\r", 2, "CR"},
+		{"a\n\nb", 3, "LF"},
+	}
+	for _, tc := range testCases {
+		s := collect(t, []byte(tc.input))
+		if s.Lines != tc.lines || s.LineEndingStyle() != tc.style {
+			t.Errorf("%q has %d lines of %s, but expected value is %d lines of %s", tc.input, s.Lines, s.LineEndingStyle(), tc.lines, tc.style)
+		}
+	}
+}
+
+func TestSorted(t *testing.T) {
+	sorted := stats.Sorted(map[string]int64{"b": 1, "a": 1, "c": 2})
+	expected := []stats.Count{{Key: "c", Count: 2}, {Key: "a", Count: 1}, {Key: "b", Count: 1}}
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("stats.Sorted returns %v, but expected value is %v", sorted, expected)
+	}
+}
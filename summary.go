@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/stats"
+	"github.com/moba1/usd/unicode"
+	"golang.org/x/text/unicode/runenames"
+)
+
+var (
+	showSummary bool
+	summaryTop  = 10
+)
+
+// summaryFlags adds the -summary and -top options to a dump subcommand.
+func summaryFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&showSummary, "summary", false, "print statistics instead of the table")
+	fs.IntVar(&summaryTop, "top", summaryTop, "`number` of the most frequent characters in the summary")
+}
+
+// checkSummaryFlags rejects a negative -top.
+func checkSummaryFlags() {
+	if summaryTop < 0 {
+		log.Fatalf("can't use -top %d (reason; the number must not be negative)\n", summaryTop)
+	}
+}
+
+func summarize() error {
+	s := stats.New()
+	scanner := unicode.NewScanner(os.Stdin, reader)
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("can't read input (reason; %s)", err.Error())
+		}
+		s.Add(c)
+	}
+	s.Finish()
+
+	table := newTableEncoder(tableOutput)
+	if !noHeader {
+		table.SetHeader([]string{"Group", "Item", "Value"})
+	}
+	appendCount := func(group, item string, count int64) {
+		table.Append([]string{group, item, strconv.FormatInt(count, 10)})
+	}
+	appendCounts := func(group string, counts map[string]int64) {
+		for _, c := range stats.Sorted(counts) {
+			appendCount(group, c.Key, c.Count)
+		}
+	}
+
+	appendCount("Total", "Bytes", s.Bytes)
+	appendCount("Total", "Code Points", s.CodePoints)
+	appendCount("Total", "Graphemes", s.Graphemes)
+	appendCount("Total", "Lines", s.Lines)
+	bom := "No"
+	if s.BOM {
+		bom = "Yes"
+	}
+	table.Append([]string{"Encoding", "BOM", bom})
+	table.Append([]string{"Line Ending", "Style", s.LineEndingStyle()})
+	appendCounts("Line Ending", s.LineEndings)
+	appendCounts("Error", s.Errors)
+	appendCounts("Plane", s.Planes)
+	appendCounts("Block", s.Blocks)
+	appendCounts("Script", s.Scripts)
+	appendCounts("Category", s.Categories)
+	for _, c := range s.Top(summaryTop) {
+		appendCount("Top", fmt.Sprintf("%s %U %s", encoder.GraphicString(c.Char), c.Char, runenames.Name(c.Char)), c.Count)
+	}
+	return table.Render()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/transcode"
+	"github.com/moba1/usd/unicode"
+)
+
+const transcodeCmdName = "transcode"
+
+func parseTranscodeCmd(args []string) func() error {
+	transcodeCmd := flag.NewFlagSet(transcodeCmdName, flag.ExitOnError)
+	var (
+		from       = "UTF8"
+		to         = "UTF8"
+		fromEndian = unicode.BigEndian
+		toEndian   = unicode.BigEndian
+		invalid    = transcode.Fail
+		bom        = transcode.KeepBOM
+		report     bool
+	)
+	charsets := strings.Join(charset.Names(), "|")
+	transcodeCmd.Func("from", fmt.Sprintf("input `charset`. default is 'UTF8' (value: %s)", charsets), func(s string) error {
+		return parseCharset(&from, s)
+	})
+	transcodeCmd.Func("to", fmt.Sprintf("output `charset`. default is 'UTF8' (value: %s)", charsets), func(s string) error {
+		return parseCharset(&to, s)
+	})
+	transcodeCmd.Func("fromEndian", "input `endian` of UTF16 and UTF32. default is 'Big' (value: Big|Little)", func(s string) error {
+		return parseEndian(&fromEndian, s)
+	})
+	transcodeCmd.Func("toEndian", "output `endian` of UTF16 and UTF32. default is 'Big' (value: Big|Little)", func(s string) error {
+		return parseEndian(&toEndian, s)
+	})
+	transcodeCmd.Func("invalid", "`policy` for invalid input and unencodable characters. default is 'Fail' (value: Fail|Replace|Escape)", func(s string) error {
+		switch s {
+		case "Fail":
+			invalid = transcode.Fail
+		case "Replace":
+			invalid = transcode.Replace
+		case "Escape":
+			invalid = transcode.Escape
+		default:
+			return fmt.Errorf("invalid policy: %s", s)
+		}
+		return nil
+	})
+	transcodeCmd.Func("bom", "byte order mark `policy`. default is 'Keep' (value: Keep|Add|Strip)", func(s string) error {
+		switch s {
+		case "Keep":
+			bom = transcode.KeepBOM
+		case "Add":
+			bom = transcode.AddBOM
+		case "Strip":
+			bom = transcode.StripBOM
+		default:
+			return fmt.Errorf("invalid policy: %s", s)
+		}
+		return nil
+	})
+	transcodeCmd.BoolVar(&report, "report", false, "write changes to stderr")
+	transcodeCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", transcodeCmdName),
+			fmt.Sprintf("  %s [option]", transcodeCmdName),
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(transcodeCmd.Output(), stmt)
+		}
+		transcodeCmd.PrintDefaults()
+	}
+	if err := transcodeCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+
+	return func() error {
+		fromCharset, err := charset.Lookup(from, fromEndian)
+		if err != nil {
+			return err
+		}
+		toCharset, err := charset.Lookup(to, toEndian)
+		if err != nil {
+			return err
+		}
+		transcoder := transcode.Transcoder{
+			From:    fromCharset,
+			To:      toCharset,
+			Invalid: invalid,
+			BOM:     bom,
+		}
+
+		if !report {
+			return transcoder.Transcode(os.Stdin, tableOutput, nil)
+		}
+		changeTable := newTableEncoder(os.Stderr)
+		if !noHeader {
+			changeTable.SetHeader([]string{"Offset", "Input", "Output", "Reason"})
+		}
+		err = transcoder.Transcode(os.Stdin, tableOutput, func(c transcode.Change) {
+			changeTable.Append([]string{
+				strconv.FormatInt(c.Offset, 10),
+				encoder.HexString(c.Input),
+				encoder.HexString(c.Output),
+				c.Reason,
+			})
+		})
+		if renderErr := changeTable.Render(); err == nil {
+			err = renderErr
+		}
+		return err
+	}
+}
@@ -0,0 +1,204 @@
+package transcode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/unicode"
+)
+
+// InvalidPolicy decides what happens to input that the source charset can't
+// decode and to characters that the destination charset can't encode.
+type InvalidPolicy int
+
+const (
+	Fail InvalidPolicy = iota
+	Replace
+	Escape
+)
+
+type BOMPolicy int
+
+const (
+	KeepBOM BOMPolicy = iota
+	AddBOM
+	StripBOM
+)
+
+// Change describes a place where the output is not a plain conversion of the
+// input.
+type Change struct {
+	Offset int64
+	Input  []byte
+	Output []byte
+	Reason string
+}
+
+type InvalidInputErr struct {
+	offset int64
+	err    error
+}
+
+func (e *InvalidInputErr) Error() string {
+	return fmt.Sprintf("invalid input at offset %d (reason; %s)", e.offset, e.err.Error())
+}
+
+func (e *InvalidInputErr) Unwrap() error {
+	return e.err
+}
+
+func (e *InvalidInputErr) Offset() int64 {
+	return e.offset
+}
+
+type Transcoder struct {
+	From    *charset.Charset
+	To      *charset.Charset
+	Invalid InvalidPolicy
+	BOM     BOMPolicy
+}
+
+// Transcode streams r to w. report is called for every Change and may be nil.
+func (t *Transcoder) Transcode(r io.Reader, w io.Writer, report func(Change)) error {
+	if report == nil {
+		report = func(Change) {}
+	}
+	if t.BOM == AddBOM && t.To.BOM() == nil {
+		return fmt.Errorf("%s has no byte order mark", t.To.Name())
+	}
+
+	out := bufio.NewWriter(w)
+	err := t.transcode(bufio.NewReader(r), out, report)
+	// the output converted before a failure is kept
+	if flushErr := out.Flush(); err == nil && flushErr != nil {
+		err = fmt.Errorf("can't write output (reason; %s)", flushErr.Error())
+	}
+	return err
+}
+
+func (t *Transcoder) transcode(in *bufio.Reader, out *bufio.Writer, report func(Change)) error {
+	write := func(bs []byte) error {
+		if _, err := out.Write(bs); err != nil {
+			return fmt.Errorf("can't write output (reason; %s)", err.Error())
+		}
+		return nil
+	}
+
+	var offset int64
+	first := true
+	for {
+		c, bs, err := t.From.Read(in)
+		if err == io.EOF {
+			if first && t.BOM == AddBOM {
+				if err := write(t.To.BOM()); err != nil {
+					return err
+				}
+				report(Change{Offset: 0, Output: t.To.BOM(), Reason: "BOM added"})
+			}
+			break
+		}
+
+		var invalidSequenceErr *unicode.InvalidSequenceErr
+		var unexpectedEofErr *unicode.UnexpectedEofErr
+		var invalid []byte
+		switch {
+		case errors.As(err, &invalidSequenceErr):
+			invalid = invalidSequenceErr.Sequences()
+		case errors.As(err, &unexpectedEofErr):
+			invalid = unexpectedEofErr.Sequences()
+		case err != nil:
+			return fmt.Errorf("can't read input (reason; %s)", err.Error())
+		}
+
+		if first {
+			first = false
+			isBOM := invalid == nil && c == 0xFEFF
+			if isBOM && t.BOM == StripBOM {
+				report(Change{Offset: offset, Input: bs, Reason: "BOM stripped"})
+				offset += int64(len(bs))
+				continue
+			}
+			if !isBOM && t.BOM == AddBOM {
+				if err := write(t.To.BOM()); err != nil {
+					return err
+				}
+				report(Change{Offset: offset, Output: t.To.BOM(), Reason: "BOM added"})
+			}
+		}
+
+		if invalid != nil {
+			converted, err := t.convertInvalid(invalid)
+			if err != nil {
+				return &InvalidInputErr{offset: offset, err: err}
+			}
+			if err := write(converted); err != nil {
+				return err
+			}
+			report(Change{Offset: offset, Input: invalid, Output: converted, Reason: "invalid sequence"})
+			offset += int64(len(invalid))
+			continue
+		}
+
+		converted, err := t.To.Encode(c)
+		if err != nil {
+			converted, err = t.convertUnencodable(c, err)
+			if err != nil {
+				return &InvalidInputErr{offset: offset, err: err}
+			}
+			report(Change{Offset: offset, Input: bs, Output: converted, Reason: fmt.Sprintf("%U unencodable", c)})
+		}
+		if err := write(converted); err != nil {
+			return err
+		}
+		offset += int64(len(bs))
+	}
+	return nil
+}
+
+func (t *Transcoder) convertInvalid(invalid []byte) ([]byte, error) {
+	switch t.Invalid {
+	case Replace:
+		return t.replacement(), nil
+	case Escape:
+		var escaped []byte
+		for _, b := range invalid {
+			escaped = append(escaped, t.encodeASCII(fmt.Sprintf(`\x%02X`, b))...)
+		}
+		return escaped, nil
+	}
+	return nil, unicode.NewInvalidSequenceErr(invalid)
+}
+
+func (t *Transcoder) convertUnencodable(c rune, err error) ([]byte, error) {
+	switch t.Invalid {
+	case Replace:
+		return t.replacement(), nil
+	case Escape:
+		if c > 0xFFFF {
+			return t.encodeASCII(fmt.Sprintf(`\U%08X`, c)), nil
+		}
+		return t.encodeASCII(fmt.Sprintf(`\u%04X`, c)), nil
+	}
+	return nil, err
+}
+
+// replacement is U+FFFD, or '?' where the destination has no U+FFFD.
+func (t *Transcoder) replacement() []byte {
+	if bs, err := t.To.Encode(0xFFFD); err == nil {
+		return bs
+	}
+	return t.encodeASCII("?")
+}
+
+// every supported charset can encode ASCII
+func (t *Transcoder) encodeASCII(s string) []byte {
+	var bs []byte
+	for _, c := range s {
+		encoded, _ := t.To.Encode(c)
+		bs = append(bs, encoded...)
+	}
+	return bs
+}
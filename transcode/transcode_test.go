@@ -0,0 +1,170 @@
+package transcode_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/charset"
+	"github.com/moba1/usd/transcode"
+	"github.com/moba1/usd/unicode"
+)
+
+func lookup(t *testing.T, name string, endian unicode.Endian) *charset.Charset {
+	c, err := charset.Lookup(name, endian)
+	if err != nil {
+		t.Fatalf("charset.Lookup(%q) returns error: %v", name, err)
+	}
+	return c
+}
+
+func TestTranscoder_Transcode(t *testing.T) {
+	testCases := []struct {
+		title      string
+		transcoder transcode.Transcoder
+		input      []byte
+		output     []byte
+		changes    []transcode.Change
+	}{
+		{
+			title: "UTF-8 to UTF-16LE",
+			transcoder: transcode.Transcoder{
+				From: lookup(t, "UTF8", unicode.BigEndian),
+				To:   lookup(t, "UTF16", unicode.LittleEndian),
+			},
+			input:  []byte("aあ🐧"),
+			output: []byte{0x61, 0x00, 0x42, 0x30, 0x3D, 0xD8, 0x27, 0xDC},
+		},
+		{
+			title: "replace invalid sequence",
+			transcoder: transcode.Transcoder{
+				From:    lookup(t, "UTF8", unicode.BigEndian),
+				To:      lookup(t, "UTF8", unicode.BigEndian),
+				Invalid: transcode.Replace,
+			},
+			input:  []byte{0x61, 0xFE, 0x62},
+			output: []byte("a�b"),
+			changes: []transcode.Change{
+				{Offset: 1, Input: []byte{0xFE}, Output: []byte("�"), Reason: "invalid sequence"},
+			},
+		},
+		{
+			title: "escape invalid sequence",
+			transcoder: transcode.Transcoder{
+				From:    lookup(t, "UTF8", unicode.BigEndian),
+				To:      lookup(t, "UTF8", unicode.BigEndian),
+				Invalid: transcode.Escape,
+			},
+			input:  []byte{0xE3, 0x81},
+			output: []byte(`\xE3\x81`),
+			changes: []transcode.Change{
+				{Offset: 0, Input: []byte{0xE3, 0x81}, Output: []byte(`\xE3\x81`), Reason: "invalid sequence"},
+			},
+		},
+		{
+			title: "escape unencodable character",
+			transcoder: transcode.Transcoder{
+				From:    lookup(t, "UTF8", unicode.BigEndian),
+				To:      lookup(t, "ShiftJIS", unicode.BigEndian),
+				Invalid: transcode.Escape,
+			},
+			input:  []byte("あ🐧"),
+			output: append([]byte{0x82, 0xA0}, []byte(`\U0001F427`)...),
+			changes: []transcode.Change{
+				{Offset: 3, Input: []byte("🐧"), Output: []byte(`\U0001F427`), Reason: "U+1F427 unencodable"},
+			},
+		},
+		{
+			title: "replace unencodable character",
+			transcoder: transcode.Transcoder{
+				From:    lookup(t, "UTF8", unicode.BigEndian),
+				To:      lookup(t, "Latin1", unicode.BigEndian),
+				Invalid: transcode.Replace,
+			},
+			input:  []byte("éあ"),
+			output: []byte{0xE9, '?'},
+			changes: []transcode.Change{
+				{Offset: 2, Input: []byte("あ"), Output: []byte("?"), Reason: "U+3042 unencodable"},
+			},
+		},
+		{
+			title: "add BOM",
+			transcoder: transcode.Transcoder{
+				From: lookup(t, "UTF8", unicode.BigEndian),
+				To:   lookup(t, "UTF16", unicode.BigEndian),
+				BOM:  transcode.AddBOM,
+			},
+			input:  []byte("a"),
+			output: []byte{0xFE, 0xFF, 0x00, 0x61},
+			changes: []transcode.Change{
+				{Offset: 0, Output: []byte{0xFE, 0xFF}, Reason: "BOM added"},
+			},
+		},
+		{
+			title: "keep existing BOM",
+			transcoder: transcode.Transcoder{
+				From: lookup(t, "UTF16", unicode.LittleEndian),
+				To:   lookup(t, "UTF8", unicode.BigEndian),
+				BOM:  transcode.AddBOM,
+			},
+			input:  []byte{0xFF, 0xFE, 0x61, 0x00},
+			output: []byte{0xEF, 0xBB, 0xBF, 0x61},
+		},
+		{
+			title: "strip BOM",
+			transcoder: transcode.Transcoder{
+				From: lookup(t, "UTF8", unicode.BigEndian),
+				To:   lookup(t, "UTF32", unicode.LittleEndian),
+				BOM:  transcode.StripBOM,
+			},
+			input:  []byte{0xEF, 0xBB, 0xBF, 0x61},
+			output: []byte{0x61, 0x00, 0x00, 0x00},
+			changes: []transcode.Change{
+				{Offset: 0, Input: []byte{0xEF, 0xBB, 0xBF}, Reason: "BOM stripped"},
+			},
+		},
+	}
+	for _, c := range testCases {
+		var output bytes.Buffer
+		var changes []transcode.Change
+		err := c.transcoder.Transcode(bytes.NewBuffer(c.input), &output, func(change transcode.Change) {
+			changes = append(changes, change)
+		})
+		if err != nil {
+			t.Errorf("%s: Transcoder.Transcode returns error: %v", c.title, err)
+			continue
+		}
+		if !reflect.DeepEqual(output.Bytes(), c.output) {
+			t.Errorf("%s: Transcoder.Transcode writes %v, but expected value is %v", c.title, output.Bytes(), c.output)
+		}
+		if !reflect.DeepEqual(changes, c.changes) {
+			t.Errorf("%s: Transcoder.Transcode reports %v, but expected value is %v", c.title, changes, c.changes)
+		}
+	}
+}
+
+func TestTranscoder_Transcode_Fail(t *testing.T) {
+	transcoder := transcode.Transcoder{
+		From: lookup(t, "UTF8", unicode.BigEndian),
+		To:   lookup(t, "UTF16", unicode.BigEndian),
+	}
+	err := transcoder.Transcode(bytes.NewBuffer([]byte{0x61, 0x62, 0xFE}), &bytes.Buffer{}, nil)
+	var invalidInputErr *transcode.InvalidInputErr
+	if !errors.As(err, &invalidInputErr) {
+		t.Fatalf("Transcoder.Transcode return non-InvalidInputErr: %v", err)
+	}
+	if invalidInputErr.Offset() != 2 {
+		t.Errorf("InvalidInputErr.Offset returns %d, but expected value is 2", invalidInputErr.Offset())
+	}
+	var invalidSequenceErr *unicode.InvalidSequenceErr
+	if !errors.As(err, &invalidSequenceErr) {
+		t.Errorf("InvalidInputErr doesn't wrap InvalidSequenceErr: %v", err)
+	}
+
+	transcoder.To = lookup(t, "ShiftJIS", unicode.BigEndian)
+	transcoder.BOM = transcode.AddBOM
+	if err := transcoder.Transcode(bytes.NewBuffer([]byte("a")), &bytes.Buffer{}, nil); err == nil {
+		t.Errorf("Transcoder.Transcode adds BOM to ShiftJIS")
+	}
+}
@@ -0,0 +1,90 @@
+package ucd
+
+import (
+	"bufio"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseFields calls fn with the semicolon separated fields of each data line
+// of a UCD file. Comments and surrounding spaces are removed.
+func ParseFields(txt string, fn func(fields []string)) {
+	scanner := bufio.NewScanner(strings.NewReader(txt))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		fn(fields)
+	}
+}
+
+// ParseDataRange parses "0041" or "0041..005A" of a UCD file.
+func ParseDataRange(s string) (first rune, last rune) {
+	bounds := strings.SplitN(s, "..", 2)
+	f, _ := strconv.ParseUint(bounds[0], 16, 32)
+	l := f
+	if len(bounds) == 2 {
+		l, _ = strconv.ParseUint(bounds[1], 16, 32)
+	}
+	return rune(f), rune(l)
+}
+
+// ParseDataCodePoints parses a sequence of code points of a UCD file such as
+// "0041 0301".
+func ParseDataCodePoints(s string) []rune {
+	var cps []rune
+	for _, field := range strings.Fields(s) {
+		c, _ := strconv.ParseUint(field, 16, 32)
+		cps = append(cps, rune(c))
+	}
+	return cps
+}
+
+// RangeValue is the property value of the code points from First to Last.
+type RangeValue struct {
+	First rune
+	Last  rune
+	Value string
+}
+
+// RangeTable is the values of a property sorted by code point, without
+// overlapping ranges.
+type RangeTable []RangeValue
+
+// ParseRangeTable parses a UCD file whose first field is a range and second
+// field a value, such as Blocks.txt. The ranges of the file must be sorted.
+func ParseRangeTable(txt string) RangeTable {
+	var table RangeTable
+	ParseFields(txt, func(fields []string) {
+		first, last := ParseDataRange(fields[0])
+		table = append(table, RangeValue{First: first, Last: last, Value: fields[1]})
+	})
+	return table
+}
+
+// Sort sorts the ranges by code point.
+func (t RangeTable) Sort() {
+	sort.Slice(t, func(i, j int) bool {
+		return t[i].First < t[j].First
+	})
+}
+
+// Lookup returns the value of c. ok is false if no range contains c.
+func (t RangeTable) Lookup(c rune) (value string, ok bool) {
+	i := sort.Search(len(t), func(i int) bool {
+		return t[i].Last >= c
+	})
+	if i < len(t) && t[i].First <= c {
+		return t[i].Value, true
+	}
+	return "", false
+}
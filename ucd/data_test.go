@@ -0,0 +1,53 @@
+package ucd_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/ucd"
+)
+
+func TestParseFields(t *testing.T) {
+	txt := "# comment\n\n0041..005A ; Upper # Lu  [26]\n0061;Lower\n"
+	var actual [][]string
+	ucd.ParseFields(txt, func(fields []string) {
+		actual = append(actual, fields)
+	})
+	expected := [][]string{{"0041..005A", "Upper"}, {"0061", "Lower"}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ucd.ParseFields calls fn with %q, but expected value is %q", actual, expected)
+	}
+}
+
+func TestParseDataCodePoints(t *testing.T) {
+	if first, last := ucd.ParseDataRange("0041..005A"); first != 'A' || last != 'Z' {
+		t.Errorf("ucd.ParseDataRange returns (%U, %U), but expected value is (U+0041, U+005A)", first, last)
+	}
+	if first, last := ucd.ParseDataRange("1F600"); first != 0x1F600 || last != 0x1F600 {
+		t.Errorf("ucd.ParseDataRange returns (%U, %U), but expected value is (U+1F600, U+1F600)", first, last)
+	}
+	if cps := ucd.ParseDataCodePoints("0072 006E"); !reflect.DeepEqual(cps, []rune{'r', 'n'}) {
+		t.Errorf("ucd.ParseDataCodePoints returns %U, but expected value is [U+0072 U+006E]", cps)
+	}
+}
+
+func TestRangeTable_Lookup(t *testing.T) {
+	table := ucd.ParseRangeTable("0041..005A ; Upper\n0061..007A ; Lower\n00AA ; Lower\n")
+	testCases := []struct {
+		char  rune
+		value string
+		ok    bool
+	}{
+		{'A', "Upper", true},
+		{'Z', "Upper", true},
+		{'[', "", false},
+		{'m', "Lower", true},
+		{0xAA, "Lower", true},
+		{0xAB, "", false},
+	}
+	for _, c := range testCases {
+		if value, ok := table.Lookup(c.char); value != c.value || ok != c.ok {
+			t.Errorf("RangeTable.Lookup(%U) returns (%q, %v), but expected value is (%q, %v)", c.char, value, ok, c.value, c.ok)
+		}
+	}
+}
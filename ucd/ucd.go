@@ -0,0 +1,304 @@
+// Package ucd looks up character properties which are missing from the
+// standard unicode package.
+package ucd
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/runenames"
+	"golang.org/x/text/width"
+)
+
+//go:embed data/Blocks.txt
+var blocksTxt string
+
+//go:embed data/NameAliases.txt
+var nameAliasesTxt string
+
+//go:embed data/ScriptExtensions.txt
+var scriptExtensionsTxt string
+
+type Block struct {
+	Name  string
+	First rune
+	Last  rune
+}
+
+type Alias struct {
+	Name string
+	Type string
+}
+
+var (
+	loadOnce   sync.Once
+	blocks     []Block
+	aliases    map[rune][]Alias
+	scripts    RangeTable
+	scriptExts RangeTable
+	categories RangeTable
+)
+
+func rangeTableValues(tables map[string]*unicode.RangeTable, accept func(string) bool) RangeTable {
+	var values RangeTable
+	for name, table := range tables {
+		if !accept(name) {
+			continue
+		}
+		for _, r16 := range table.R16 {
+			for c := rune(r16.Lo); c <= rune(r16.Hi); c += rune(r16.Stride) {
+				values = append(values, RangeValue{First: c, Last: c, Value: name})
+				if r16.Stride == 1 {
+					values[len(values)-1].Last = rune(r16.Hi)
+					break
+				}
+			}
+		}
+		for _, r32 := range table.R32 {
+			for c := rune(r32.Lo); c <= rune(r32.Hi); c += rune(r32.Stride) {
+				values = append(values, RangeValue{First: c, Last: c, Value: name})
+				if r32.Stride == 1 {
+					values[len(values)-1].Last = rune(r32.Hi)
+					break
+				}
+			}
+		}
+	}
+	values.Sort()
+	return values
+}
+
+func load() {
+	loadOnce.Do(func() {
+		ParseFields(blocksTxt, func(fields []string) {
+			first, last := ParseDataRange(fields[0])
+			blocks = append(blocks, Block{Name: fields[1], First: first, Last: last})
+		})
+		aliases = map[rune][]Alias{}
+		ParseFields(nameAliasesTxt, func(fields []string) {
+			c, _ := ParseDataRange(fields[0])
+			aliases[c] = append(aliases[c], Alias{Name: fields[1], Type: fields[2]})
+		})
+		scriptExts = ParseRangeTable(scriptExtensionsTxt)
+		scripts = rangeTableValues(unicode.Scripts, func(string) bool {
+			return true
+		})
+		categories = rangeTableValues(unicode.Categories, func(name string) bool {
+			// LC is the union of Lu, Ll and Lt in newer versions of Go
+			return len(name) == 2 && name != "LC"
+		})
+	})
+}
+
+// Blocks returns all blocks in code point order.
+func Blocks() []Block {
+	load()
+	return blocks
+}
+
+// BlockOf returns the block which contains c. ok is false for No_Block.
+func BlockOf(c rune) (block Block, ok bool) {
+	load()
+	i := sort.Search(len(blocks), func(i int) bool {
+		return blocks[i].Last >= c
+	})
+	if i < len(blocks) && blocks[i].First <= c {
+		return blocks[i], true
+	}
+	return Block{}, false
+}
+
+// LookupBlock finds a block by name. As UAX #44 recommends, case, whitespace,
+// hyphens and underscores are ignored.
+func LookupBlock(name string) (Block, error) {
+	load()
+	for _, b := range blocks {
+		if looseName(b.Name) == looseName(name) {
+			return b, nil
+		}
+	}
+	return Block{}, fmt.Errorf("unknown block: %s", name)
+}
+
+func looseName(name string) string {
+	return strings.Map(func(c rune) rune {
+		if unicode.IsSpace(c) || c == '-' || c == '_' {
+			return -1
+		}
+		return unicode.ToLower(c)
+	}, name)
+}
+
+// Aliases returns the formal name aliases of c.
+func Aliases(c rune) []Alias {
+	load()
+	return aliases[c]
+}
+
+// Script returns the Script property value of c, such as "Latin". Code
+// points without script are "Unknown".
+func Script(c rune) string {
+	load()
+	if s, ok := scripts.Lookup(c); ok {
+		return s
+	}
+	return "Unknown"
+}
+
+// ScriptExtensions returns the Script_Extensions property value of c: the
+// scripts c is used with. For most characters it is only Script(c).
+func ScriptExtensions(c rune) []string {
+	load()
+	if s, ok := scriptExts.Lookup(c); ok {
+		return strings.Fields(s)
+	}
+	return []string{Script(c)}
+}
+
+// LookupScript finds a script by name, ignoring case, whitespace, hyphens
+// and underscores.
+func LookupScript(name string) (string, error) {
+	for s := range unicode.Scripts {
+		if looseName(s) == looseName(name) {
+			return s, nil
+		}
+	}
+	if looseName(name) == "unknown" {
+		return "Unknown", nil
+	}
+	return "", fmt.Errorf("unknown script: %s", name)
+}
+
+// Category returns the two letter General_Category of c. Unassigned code
+// points are "Cn".
+func Category(c rune) string {
+	load()
+	if s, ok := categories.Lookup(c); ok {
+		return s
+	}
+	return "Cn"
+}
+
+// LookupCategory finds a General_Category value such as "Lu" or a major
+// class such as "L", ignoring case.
+func LookupCategory(name string) (string, error) {
+	for category := range unicode.Categories {
+		if (len(category) <= 2 && category != "LC") && strings.EqualFold(category, name) {
+			return category, nil
+		}
+	}
+	if strings.EqualFold(name, "Cn") {
+		return "Cn", nil
+	}
+	return "", fmt.Errorf("unknown category: %s", name)
+}
+
+// MatchCategory reports whether the General_Category of c is category, which
+// is either a two letter value such as "Lu" or a major class such as "L".
+func MatchCategory(c rune, category string) bool {
+	return strings.HasPrefix(Category(c), category)
+}
+
+// DisplayWidth is the number of columns of s in a terminal or a table, where
+// East Asian wide and fullwidth characters take two columns, and combining
+// marks and format characters none.
+func DisplayWidth(s string) int {
+	w := 0
+	for _, c := range s {
+		switch {
+		case unicode.In(c, unicode.Mn, unicode.Me, unicode.Cf):
+		case width.LookupRune(c).Kind() == width.EastAsianWide, width.LookupRune(c).Kind() == width.EastAsianFullwidth:
+			w += 2
+		default:
+			w++
+		}
+	}
+	return w
+}
+
+// IsDefaultIgnorable reports whether c has the Default_Ignorable_Code_Point
+// property, which is derived as described in UAX #44.
+func IsDefaultIgnorable(c rune) bool {
+	if unicode.Is(unicode.White_Space, c) || unicode.Is(unicode.Prepended_Concatenation_Mark, c) ||
+		(c >= 0xFFF9 && c <= 0xFFFB) || (c >= 0x13430 && c <= 0x1343F) {
+		return false
+	}
+	return unicode.In(c, unicode.Other_Default_Ignorable_Code_Point, unicode.Cf, unicode.Variation_Selector)
+}
+
+// Name returns the Name property of c. Unlike runenames.Name, names of
+// ideographs and Hangul syllables are derived as described in UAX #44, and
+// code points without a name, such as controls, are "".
+func Name(c rune) string {
+	name := runenames.Name(c)
+	if !strings.HasPrefix(name, "<") {
+		return name
+	}
+	switch {
+	case strings.HasPrefix(name, "<CJK Ideograph"):
+		return fmt.Sprintf("CJK UNIFIED IDEOGRAPH-%04X", c)
+	case strings.HasPrefix(name, "<Tangut Ideograph"):
+		return fmt.Sprintf("TANGUT IDEOGRAPH-%04X", c)
+	case name == "<Hangul Syllable>":
+		return hangulSyllableName(c)
+	}
+	return ""
+}
+
+var (
+	jamoL = []string{"G", "GG", "N", "D", "DD", "R", "M", "B", "BB", "S", "SS", "", "J", "JJ", "C", "K", "T", "P", "H"}
+	jamoV = []string{"A", "AE", "YA", "YAE", "EO", "E", "YEO", "YE", "O", "WA", "WAE", "OE", "YO", "U", "WEO", "WE", "WI", "YU", "EU", "YI", "I"}
+	jamoT = []string{"", "G", "GG", "GS", "N", "NJ", "NH", "D", "L", "LG", "LM", "LB", "LS", "LT", "LP", "LH", "M", "B", "BS", "S", "SS", "NG", "J", "C", "K", "T", "P", "H"}
+)
+
+func hangulSyllableName(c rune) string {
+	s := int(c - 0xAC00)
+	l := s / (len(jamoV) * len(jamoT))
+	v := s % (len(jamoV) * len(jamoT)) / len(jamoT)
+	t := s % len(jamoT)
+	return "HANGUL SYLLABLE " + jamoL[l] + jamoV[v] + jamoT[t]
+}
+
+// ParseCodePoint parses "U+1F427", "0x1F427" or a single character such as
+// "🐧". A hexadecimal number needs the prefix, so "4" is U+0034 and "41" is
+// an error.
+func ParseCodePoint(s string) (rune, error) {
+	switch {
+	case strings.HasPrefix(s, "U+"), strings.HasPrefix(s, "u+"), strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		c, err := strconv.ParseUint(s[2:], 16, 32)
+		if err != nil || c > unicode.MaxRune {
+			return 0, fmt.Errorf("invalid code point: %s", s)
+		}
+		return rune(c), nil
+	}
+	if rs := []rune(s); len(rs) == 1 {
+		return rs[0], nil
+	}
+	return 0, fmt.Errorf("invalid code point: %s (hexadecimal numbers need the U+ or 0x prefix)", s)
+}
+
+// ParseRange parses "U+3040..U+309F". A single code point is a range of
+// itself.
+func ParseRange(s string) (first rune, last rune, err error) {
+	bounds := strings.SplitN(s, "..", 2)
+	first, err = ParseCodePoint(bounds[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	last = first
+	if len(bounds) == 2 {
+		last, err = ParseCodePoint(bounds[1])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if first > last {
+		return 0, 0, fmt.Errorf("invalid range: %s", s)
+	}
+	return first, last, nil
+}
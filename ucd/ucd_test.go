@@ -0,0 +1,200 @@
+package ucd_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moba1/usd/ucd"
+)
+
+func TestBlockOf(t *testing.T) {
+	b, ok := ucd.BlockOf('あ')
+	if !ok || b.Name != "Hiragana" || b.First != 0x3040 || b.Last != 0x309F {
+		t.Errorf("ucd.BlockOf('あ') returns %v, but expected block is Hiragana (U+3040..U+309F)", b)
+	}
+	if b, ok := ucd.BlockOf(0xEFFFF); ok {
+		t.Errorf("ucd.BlockOf(U+EFFFF) returns %v, but expected No_Block", b)
+	}
+	if blocks := ucd.Blocks(); len(blocks) == 0 || blocks[0].Name != "Basic Latin" {
+		t.Errorf("ucd.Blocks doesn't start with Basic Latin: %v", blocks)
+	}
+}
+
+func TestLookupBlock(t *testing.T) {
+	b, err := ucd.LookupBlock("latin_extended a")
+	if err != nil || b.Name != "Latin Extended-A" {
+		t.Errorf("ucd.LookupBlock returns (%v, %v), but expected block is Latin Extended-A", b, err)
+	}
+	if _, err := ucd.LookupBlock("Klingon"); err == nil {
+		t.Errorf("ucd.LookupBlock finds unknown block")
+	}
+}
+
+func TestAliases(t *testing.T) {
+	expected := []ucd.Alias{
+		{Name: "BYTE ORDER MARK", Type: "alternate"},
+		{Name: "BOM", Type: "abbreviation"},
+		{Name: "ZWNBSP", Type: "abbreviation"},
+	}
+	if aliases := ucd.Aliases(0xFEFF); !reflect.DeepEqual(aliases, expected) {
+		t.Errorf("ucd.Aliases(U+FEFF) returns %v, but expected value is %v", aliases, expected)
+	}
+	if aliases := ucd.Aliases('a'); aliases != nil {
+		t.Errorf("ucd.Aliases('a') returns %v, but expected value is nil", aliases)
+	}
+}
+
+func TestScript(t *testing.T) {
+	testCases := map[rune]string{
+		'a':      "Latin",
+		'あ':      "Hiragana",
+		'1':      "Common",
+		0x0301:   "Inherited",
+		0x10FFFF: "Unknown",
+	}
+	for c, expected := range testCases {
+		if s := ucd.Script(c); s != expected {
+			t.Errorf("ucd.Script(%U) returns %q, but expected value is %q", c, s, expected)
+		}
+	}
+	if s, err := ucd.LookupScript("han"); err != nil || s != "Han" {
+		t.Errorf("ucd.LookupScript returns (%q, %v), but expected value is Han", s, err)
+	}
+}
+
+func TestLookupCategory(t *testing.T) {
+	for name, expected := range map[string]string{"Lu": "Lu", "zs": "Zs", "L": "L", "Cn": "Cn"} {
+		if category, err := ucd.LookupCategory(name); err != nil || category != expected {
+			t.Errorf("ucd.LookupCategory(%q) returns (%q, %v), but expected value is %s", name, category, err, expected)
+		}
+	}
+	for _, name := range []string{"Zz", "LC", "Letter", ""} {
+		if category, err := ucd.LookupCategory(name); err == nil {
+			t.Errorf("ucd.LookupCategory(%q) returns %q, but expected error", name, category)
+		}
+	}
+}
+
+func TestScriptExtensions(t *testing.T) {
+	testCases := map[rune][]string{
+		'a':    {"Latin"},
+		0x30FC: {"Hiragana", "Katakana"},
+		0x0660: {"Arabic", "Thaana", "Yezidi"},
+	}
+	for c, expected := range testCases {
+		if s := ucd.ScriptExtensions(c); !reflect.DeepEqual(s, expected) {
+			t.Errorf("ucd.ScriptExtensions(%U) returns %v, but expected value is %v", c, s, expected)
+		}
+	}
+}
+
+func TestCategory(t *testing.T) {
+	testCases := map[rune]string{
+		'A':    "Lu",
+		' ':    "Zs",
+		0x200B: "Cf",
+		0x0378: "Cn",
+	}
+	for c, expected := range testCases {
+		if category := ucd.Category(c); category != expected {
+			t.Errorf("ucd.Category(%U) returns %q, but expected value is %q", c, category, expected)
+		}
+	}
+	if !ucd.MatchCategory('A', "L") || ucd.MatchCategory('A', "Ll") {
+		t.Errorf("ucd.MatchCategory doesn't match major class")
+	}
+}
+
+func TestIsDefaultIgnorable(t *testing.T) {
+	testCases := map[rune]bool{
+		'a':     false,
+		0x00A0:  false,
+		0x00AD:  true,
+		0x200B:  true,
+		0x3164:  true,
+		0xFE0F:  true,
+		0xE0041: true,
+		0x0600:  false,
+		0xFFF9:  false,
+	}
+	for c, expected := range testCases {
+		if ignorable := ucd.IsDefaultIgnorable(c); ignorable != expected {
+			t.Errorf("ucd.IsDefaultIgnorable(%U) returns %v, but expected value is %v", c, ignorable, expected)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	testCases := map[rune]string{
+		'A':     "LATIN CAPITAL LETTER A",
+		0x4E00:  "CJK UNIFIED IDEOGRAPH-4E00",
+		0x20000: "CJK UNIFIED IDEOGRAPH-20000",
+		0xAC00:  "HANGUL SYLLABLE GA",
+		0xD7A3:  "HANGUL SYLLABLE HIH",
+		0x17000: "TANGUT IDEOGRAPH-17000",
+		0x0000:  "",
+		0xE000:  "",
+	}
+	for c, expected := range testCases {
+		if name := ucd.Name(c); name != expected {
+			t.Errorf("ucd.Name(%U) returns %q, but expected value is %q", c, name, expected)
+		}
+	}
+}
+
+func TestParseCodePoint(t *testing.T) {
+	testCases := map[string]rune{
+		"U+1F427":  '🐧',
+		"u+3042":   'あ',
+		"0x41":     'A',
+		"あ":        'あ',
+		"4":        '4',
+		"U+0034":   '4',
+		"0x34":     '4',
+		"U+10FFFF": 0x10FFFF,
+	}
+	for s, expected := range testCases {
+		c, err := ucd.ParseCodePoint(s)
+		if err != nil || c != expected {
+			t.Errorf("ucd.ParseCodePoint(%q) returns (%U, %v), but expected value is %U", s, c, err, expected)
+		}
+	}
+	for _, s := range []string{"U+110000", "U+XYZ", "", "41", "10FFFF", "0x"} {
+		if c, err := ucd.ParseCodePoint(s); err == nil {
+			t.Errorf("ucd.ParseCodePoint(%q) returns %U, but expected error", s, c)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	first, last, err := ucd.ParseRange("U+3040..U+309F")
+	if err != nil || first != 0x3040 || last != 0x309F {
+		t.Errorf("ucd.ParseRange returns (%U, %U, %v), but expected value is (U+3040, U+309F)", first, last, err)
+	}
+	first, last, err = ucd.ParseRange("U+0041")
+	if err != nil || first != 'A' || last != 'A' {
+		t.Errorf("ucd.ParseRange returns (%U, %U, %v), but expected value is (U+0041, U+0041)", first, last, err)
+	}
+	if _, _, err := ucd.ParseRange("U+309F..U+3040"); err == nil {
+		t.Errorf("ucd.ParseRange accepts reversed range")
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	testCases := []struct {
+		s     string
+		width int
+	}{
+		{"abc", 3},
+		{"あい", 4},
+		{"ｱ", 1},
+		{"Ａ", 2},
+		{"e\u0301", 1},
+		{"a\u200db", 2},
+	}
+	for _, c := range testCases {
+		if w := ucd.DisplayWidth(c.s); w != c.width {
+			t.Errorf("ucd.DisplayWidth(%q) returns %d, but expected value is %d", c.s, w, c.width)
+		}
+	}
+}
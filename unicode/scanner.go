@@ -0,0 +1,71 @@
+package unicode
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// Char is a character read by Scanner with its position in the input.
+type Char struct {
+	Rune  rune
+	Bytes []byte
+	// Offset is the byte offset from the beginning of the input.
+	Offset int64
+	// Line and Column start at 1. Column counts characters.
+	Line   int
+	Column int
+	// Err is InvalidSequenceErr or UnexpectedEofErr when Bytes couldn't be
+	// decoded. Rune is utf8.RuneError then.
+	Err error
+}
+
+// Scanner reads characters with one of the Read functions and keeps track of
+// their positions. Unlike the Read functions, it continues after invalid
+// sequences.
+type Scanner struct {
+	buf    *bufio.Reader
+	read   func(*bufio.Reader) (rune, []byte, error)
+	offset int64
+	line   int
+	column int
+}
+
+func NewScanner(r io.Reader, read func(*bufio.Reader) (rune, []byte, error)) *Scanner {
+	return &Scanner{
+		buf:  bufio.NewReader(r),
+		read: read,
+		line: 1,
+	}
+}
+
+// Scan returns the next character, io.EOF at the end of the input or an I/O
+// error.
+func (s *Scanner) Scan() (Char, error) {
+	r, bs, err := s.read(s.buf)
+	var invalidSequenceErr *InvalidSequenceErr
+	var unexpectedEofErr *UnexpectedEofErr
+	switch {
+	case errors.As(err, &invalidSequenceErr):
+		r, bs = 0xFFFD, invalidSequenceErr.Sequences()
+	case errors.As(err, &unexpectedEofErr):
+		r, bs = 0xFFFD, unexpectedEofErr.Sequences()
+	case err != nil:
+		return Char{}, err
+	}
+	s.column++
+	c := Char{
+		Rune:   r,
+		Bytes:  bs,
+		Offset: s.offset,
+		Line:   s.line,
+		Column: s.column,
+		Err:    err,
+	}
+	s.offset += int64(len(bs))
+	if err == nil && r == '\n' {
+		s.line++
+		s.column = 0
+	}
+	return c, nil
+}
@@ -0,0 +1,46 @@
+package unicode_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/moba1/usd/unicode"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	scanner := unicode.NewScanner(bytes.NewBuffer([]byte("aあ\n\xFEb")), unicode.ReadUtf8Char)
+	expected := []struct {
+		char   rune
+		offset int64
+		line   int
+		column int
+		valid  bool
+	}{
+		{char: 'a', offset: 0, line: 1, column: 1, valid: true},
+		{char: 'あ', offset: 1, line: 1, column: 2, valid: true},
+		{char: '\n', offset: 4, line: 1, column: 3, valid: true},
+		{char: 0xFFFD, offset: 5, line: 2, column: 1, valid: false},
+		{char: 'b', offset: 6, line: 2, column: 2, valid: true},
+	}
+	for _, e := range expected {
+		c, err := scanner.Scan()
+		if err != nil {
+			t.Fatalf("Scanner.Scan returns error: %v", err)
+		}
+		if c.Rune != e.char || c.Offset != e.offset || c.Line != e.line || c.Column != e.column || (c.Err == nil) != e.valid {
+			t.Errorf("Scanner.Scan returns %+v, but expected value is %+v", c, e)
+		}
+	}
+	if _, err := scanner.Scan(); err != io.EOF {
+		t.Errorf("expected EOF, but Scanner.Scan returns %v", err)
+	}
+
+	scanner = unicode.NewScanner(bytes.NewBuffer([]byte{0xE3, 0x81}), unicode.ReadUtf8Char)
+	c, err := scanner.Scan()
+	var unexpectedEofErr *unicode.UnexpectedEofErr
+	if err != nil || !errors.As(c.Err, &unexpectedEofErr) {
+		t.Errorf("Scanner.Scan returns (%+v, %v), but expected UnexpectedEofErr", c, err)
+	}
+}
@@ -3,6 +3,7 @@ package unicode
 import (
 	"bufio"
 	"fmt"
+	"io"
 )
 
 type Endian int
@@ -30,16 +31,57 @@ func (*UnknownEndianErr) Error() string {
 	return "unknown endian"
 }
 
-type UnexpectedEofErr struct{}
+type UnexpectedEofErr struct {
+	sequences []byte
+}
 
 func (*UnexpectedEofErr) Error() string {
 	return "unexpected eof"
 }
 
+// Sequences returns the bytes read before the stream ended.
+func (e *UnexpectedEofErr) Sequences() []byte {
+	return e.sequences
+}
+
+type InvalidCodePointErr struct {
+	codePoint rune
+}
+
+func (e *InvalidCodePointErr) Error() string {
+	return fmt.Sprintf("invalid code point: %U", e.codePoint)
+}
+
+func (e *InvalidCodePointErr) CodePoint() rune {
+	return e.codePoint
+}
+
+func isValidCodePoint(r rune) bool {
+	return 0 <= r && r <= 0x10FFFF && !(0xD800 <= r && r <= 0xDFFF)
+}
+
 func readMultiByte(buf *bufio.Reader, bs []byte) error {
-	n, err := buf.Read(bs)
-	if 0 < n && n < len(bs) {
-		return &UnexpectedEofErr{}
+	n, err := io.ReadFull(buf, bs)
+	if err == io.ErrUnexpectedEOF {
+		return &UnexpectedEofErr{
+			sequences: append([]byte{}, bs[:n]...),
+		}
 	}
 	return err
 }
+
+// NewInvalidSequenceErr is for readers of other encodings which report
+// errors the same way as the readers in this package.
+func NewInvalidSequenceErr(sequences []byte) *InvalidSequenceErr {
+	return &InvalidSequenceErr{
+		sequences: sequences,
+	}
+}
+
+// NewUnexpectedEofErr is for readers of other encodings which report errors
+// the same way as the readers in this package.
+func NewUnexpectedEofErr(sequences []byte) *UnexpectedEofErr {
+	return &UnexpectedEofErr{
+		sequences: sequences,
+	}
+}
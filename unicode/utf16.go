@@ -36,10 +36,27 @@ func ReadUtf16Char(endian Endian, buf *bufio.Reader) (rune, []byte, error) {
 	if err != nil {
 		return 0, nil, err
 	}
+	if 0xDC00 <= r1 && r1 <= 0xDFFF {
+		// lone low surrogate
+		return 0, nil, &InvalidSequenceErr{
+			sequences: r1Bytes,
+		}
+	}
 	if 0xD800 <= r1 && r1 <= 0xDBFF {
-		r2Bytes, err := readChar()
+		r2Bytes, err := buf.Peek(2)
+		if err == io.EOF && len(r2Bytes) == 0 {
+			return 0, nil, &UnexpectedEofErr{
+				sequences: r1Bytes,
+			}
+		}
 		if err == io.EOF {
-			err = &UnexpectedEofErr{}
+			bs := append(r1Bytes, r2Bytes...)
+			if _, err := buf.Discard(len(r2Bytes)); err != nil {
+				return 0, nil, err
+			}
+			return 0, nil, &UnexpectedEofErr{
+				sequences: bs,
+			}
 		}
 		if err != nil {
 			return 0, nil, err
@@ -49,12 +66,45 @@ func ReadUtf16Char(endian Endian, buf *bufio.Reader) (rune, []byte, error) {
 			return 0, nil, err
 		}
 		if !(0xDC00 <= r2 && r2 <= 0xDFFF) {
+			// the unit after the lone high surrogate is left in the stream
 			return 0, nil, &InvalidSequenceErr{
-				sequences: append(r1Bytes, r2Bytes...),
+				sequences: r1Bytes,
 			}
 		}
-		return utf16.Decode([]uint16{r1, r2})[0], append(r1Bytes, r2Bytes...), nil
+		bs := append(r1Bytes, r2Bytes...)
+		if _, err := buf.Discard(len(r2Bytes)); err != nil {
+			return 0, nil, err
+		}
+		return utf16.DecodeRune(rune(r1), rune(r2)), bs, nil
 	}
 
-	return utf16.Decode([]uint16{r1})[0], r1Bytes, nil
+	return rune(r1), r1Bytes, nil
+}
+
+func EncodeUtf16Char(endian Endian, r rune) ([]byte, error) {
+	var e binary.ByteOrder
+	switch endian {
+	case BigEndian:
+		e = binary.BigEndian
+	case LittleEndian:
+		e = binary.LittleEndian
+	default:
+		return nil, &UnknownEndianErr{}
+	}
+	if !isValidCodePoint(r) {
+		return nil, &InvalidCodePointErr{
+			codePoint: r,
+		}
+	}
+	var units []uint16
+	if r1, r2 := utf16.EncodeRune(r); r1 != 0xFFFD || r2 != 0xFFFD {
+		units = []uint16{uint16(r1), uint16(r2)}
+	} else {
+		units = []uint16{uint16(r)}
+	}
+	bs := make([]byte, 2*len(units))
+	for i, u := range units {
+		e.PutUint16(bs[2*i:], u)
+	}
+	return bs, nil
 }
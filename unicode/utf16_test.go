@@ -106,6 +106,9 @@ func TestReadUtf16Char_BigEndian(t *testing.T) {
 				{
 					0xD8, 0x00, 0x00, 0x61, // invalid surrogate pair
 				},
+				{
+					0xDC, 0x27, // lone low surrogate
+				},
 			},
 			lackedSeqences: [][]byte{
 				{
@@ -114,6 +117,9 @@ func TestReadUtf16Char_BigEndian(t *testing.T) {
 				{
 					0xD8, 0x00, // lack surrogate pair byte
 				},
+				{
+					0xD8, 0x00, 0xDC, // lack one byte of surrogate pair
+				},
 			},
 		},
 	}
@@ -148,6 +154,9 @@ func TestReadUtf16Char_LittleEndian(t *testing.T) {
 				{
 					0x00, 0xD8, 0x0a, 0x00, //invalid surrogate pair
 				},
+				{
+					0x27, 0xDC, // lone low surrogate
+				},
 			},
 			lackedSeqences: [][]byte{
 				{
@@ -161,3 +170,63 @@ func TestReadUtf16Char_LittleEndian(t *testing.T) {
 	}
 	testReadUtf16Char(unicode.LittleEndian, testCases, t)
 }
+
+func TestReadUtf16Char_Resynchronize(t *testing.T) {
+	buf := bufio.NewReader(bytes.NewBuffer([]byte{0xD8, 0x3D, 0x00, 0x61}))
+	_, _, err := unicode.ReadUtf16Char(unicode.BigEndian, buf)
+	var invalidSequenceErr *unicode.InvalidSequenceErr
+	if !errors.As(err, &invalidSequenceErr) {
+		t.Fatalf("ReadUtf16Char read lone high surrogate, but return non-InvalidSequenceErr: %v", err)
+	}
+	if !reflect.DeepEqual(invalidSequenceErr.Sequences(), []byte{0xD8, 0x3D}) {
+		t.Errorf("ReadUtf16Char returns invalid sequences %v, but expected value is %v", invalidSequenceErr.Sequences(), []byte{0xD8, 0x3D})
+	}
+	r, _, err := unicode.ReadUtf16Char(unicode.BigEndian, buf)
+	if err != nil || r != 'a' {
+		t.Errorf("ReadUtf16Char returns %s (error: %v) after lone high surrogate, but expected character is 'a'", strconv.QuoteRuneToGraphic(r), err)
+	}
+}
+
+func TestEncodeUtf16Char(t *testing.T) {
+	testCases := []struct {
+		endian unicode.Endian
+		char   Char
+	}{
+		{
+			endian: unicode.BigEndian,
+			char:   Char{char: 'a', byteStream: []byte{0x00, 0x61}},
+		},
+		{
+			endian: unicode.BigEndian,
+			char:   Char{char: '🐧', byteStream: []byte{0xD8, 0x3D, 0xDC, 0x27}},
+		},
+		{
+			endian: unicode.LittleEndian,
+			char:   Char{char: 'は', byteStream: []byte{0x6F, 0x30}},
+		},
+		{
+			endian: unicode.LittleEndian,
+			char:   Char{char: '🛀', byteStream: []byte{0x3D, 0xD8, 0xC0, 0xDE}},
+		},
+	}
+	for _, c := range testCases {
+		bs, err := unicode.EncodeUtf16Char(c.endian, c.char.char)
+		if err != nil {
+			t.Errorf("EncodeUtf16Char returns error: %v", err)
+		}
+		if !reflect.DeepEqual(bs, c.char.byteStream) {
+			t.Errorf("EncodeUtf16Char returns %v, but expected value is %v", bs, c.char.byteStream)
+		}
+	}
+
+	_, err := unicode.EncodeUtf16Char(unicode.BigEndian, 0xDC00)
+	var invalidCodePointErr *unicode.InvalidCodePointErr
+	if !errors.As(err, &invalidCodePointErr) {
+		t.Errorf("EncodeUtf16Char return non-InvalidCodePointErr: %v", err)
+	}
+	_, err = unicode.EncodeUtf16Char(-1, 'a')
+	var unknownEndianErr *unicode.UnknownEndianErr
+	if !errors.As(err, &unknownEndianErr) {
+		t.Errorf("EncodeUtf16Char return non-UnknownEndianErr: %v", err)
+	}
+}
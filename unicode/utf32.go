@@ -38,5 +38,30 @@ func ReadUtf32Char(endian Endian, buf *bufio.Reader) (rune, []byte, error) {
 	if readInt32Err != nil {
 		return 0, nil, readInt32Err
 	}
+	if !isValidCodePoint(r) {
+		return 0, nil, &InvalidSequenceErr{
+			sequences: bs,
+		}
+	}
 	return r, bs, nil
 }
+
+func EncodeUtf32Char(endian Endian, r rune) ([]byte, error) {
+	var e binary.ByteOrder
+	switch endian {
+	case LittleEndian:
+		e = binary.LittleEndian
+	case BigEndian:
+		e = binary.BigEndian
+	default:
+		return nil, &UnknownEndianErr{}
+	}
+	if !isValidCodePoint(r) {
+		return nil, &InvalidCodePointErr{
+			codePoint: r,
+		}
+	}
+	bs := make([]byte, 4)
+	e.PutUint32(bs, uint32(r))
+	return bs, nil
+}
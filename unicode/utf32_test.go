@@ -115,6 +115,14 @@ func TestReadUtf32Char_BigEndian(t *testing.T) {
 			invalidSequeces [][]byte
 			lackedSeqences  [][]byte
 		}{
+			invalidSequeces: [][]byte{
+				{
+					0x00, 0x11, 0x00, 0x00, // beyond U+10FFFF
+				},
+				{
+					0x00, 0x00, 0xD8, 0x00, // surrogate
+				},
+			},
 			lackedSeqences: [][]byte{
 				{
 					0x00, // lack 3 byte
@@ -148,6 +156,11 @@ func TestReadUtf32Char_LittleEndian(t *testing.T) {
 			invalidSequeces [][]byte
 			lackedSeqences  [][]byte
 		}{
+			invalidSequeces: [][]byte{
+				{
+					0xFF, 0xFF, 0xFF, 0xFF, // negative
+				},
+			},
 			lackedSeqences: [][]byte{
 				{
 					0x00, // lack 3 byte
@@ -160,3 +173,31 @@ func TestReadUtf32Char_LittleEndian(t *testing.T) {
 	}
 	testReadUtf32Char(unicode.LittleEndian, testCases, t)
 }
+
+func TestEncodeUtf32Char(t *testing.T) {
+	bs, err := unicode.EncodeUtf32Char(unicode.BigEndian, '△')
+	if err != nil {
+		t.Errorf("EncodeUtf32Char returns error: %v", err)
+	}
+	if expected := []byte{0x00, 0x00, 0x25, 0xB3}; !reflect.DeepEqual(bs, expected) {
+		t.Errorf("EncodeUtf32Char returns %v, but expected value is %v", bs, expected)
+	}
+	bs, err = unicode.EncodeUtf32Char(unicode.LittleEndian, '🐧')
+	if err != nil {
+		t.Errorf("EncodeUtf32Char returns error: %v", err)
+	}
+	if expected := []byte{0x27, 0xF4, 0x01, 0x00}; !reflect.DeepEqual(bs, expected) {
+		t.Errorf("EncodeUtf32Char returns %v, but expected value is %v", bs, expected)
+	}
+
+	_, err = unicode.EncodeUtf32Char(unicode.BigEndian, 0x110000)
+	var invalidCodePointErr *unicode.InvalidCodePointErr
+	if !errors.As(err, &invalidCodePointErr) {
+		t.Errorf("EncodeUtf32Char return non-InvalidCodePointErr: %v", err)
+	}
+	_, err = unicode.EncodeUtf32Char(-1, 'a')
+	var unknownEndianErr *unicode.UnknownEndianErr
+	if !errors.As(err, &unknownEndianErr) {
+		t.Errorf("EncodeUtf32Char return non-UnknownEndianErr: %v", err)
+	}
+}
@@ -18,27 +18,44 @@ func ReadUtf8Char(buf *bufio.Reader) (rune, []byte, error) {
 		return r, seqs, nil
 	}
 
+	// only continuation bytes are consumed, so that the next call can
+	// resynchronize on the byte that broke the sequence
 	readRemainBytes := func(n int) ([]byte, error) {
-		bs := make([]byte, n)
-		err := readMultiByte(buf, bs)
-		if err == io.EOF {
-			err = &UnexpectedEofErr{}
+		peeked, err := buf.Peek(n)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		for i, b := range peeked {
+			if b&0b1100_0000 != 0b1000_0000 {
+				bs := append([]byte{b1}, peeked[:i]...)
+				if _, err := buf.Discard(i); err != nil {
+					return nil, err
+				}
+				return nil, &InvalidSequenceErr{
+					sequences: bs,
+				}
+			}
 		}
-		if err != nil {
+		bs := append([]byte{}, peeked...)
+		if _, err := buf.Discard(len(bs)); err != nil {
 			return nil, err
 		}
+		if len(bs) < n {
+			return nil, &UnexpectedEofErr{
+				sequences: append([]byte{b1}, bs...),
+			}
+		}
 		return bs, nil
 	}
 	toRune := func(head byte, tail []byte) (rune, error) {
 		seqs := append([]byte{head}, tail...)
-		for _, b := range tail {
-			if b&0b1100_0000 != 0b1000_0000 {
-				return 0, &InvalidSequenceErr{
-					sequences: seqs,
-				}
+		r, size := utf8.DecodeRune(seqs)
+		// overlong forms, surrogates and code points beyond U+10FFFF
+		if r == utf8.RuneError && size != len(seqs) {
+			return 0, &InvalidSequenceErr{
+				sequences: seqs,
 			}
 		}
-		r, _ := utf8.DecodeRune(seqs)
 		return r, nil
 	}
 	var readByte int
@@ -63,3 +80,14 @@ func ReadUtf8Char(buf *bufio.Reader) (rune, []byte, error) {
 	}
 	return r, append([]byte{b1}, remainBytes...), nil
 }
+
+func EncodeUtf8Char(r rune) ([]byte, error) {
+	if !isValidCodePoint(r) {
+		return nil, &InvalidCodePointErr{
+			codePoint: r,
+		}
+	}
+	bs := make([]byte, utf8.RuneLen(r))
+	utf8.EncodeRune(bs, r)
+	return bs, nil
+}
@@ -69,6 +69,30 @@ func TestReadUtf8Char(t *testing.T) {
 		t.Errorf("unicode.ReadUtf8Char read invalid sequences: %v", origSeqs)
 	}
 
+	// overlong form and surrogate
+	for _, origSeqs := range [][]byte{{0xC0, 0xAF}, {0xED, 0xA0, 0x80}, {0xF4, 0x90, 0x80, 0x80}} {
+		buf = bufio.NewReader(bytes.NewBuffer(origSeqs))
+		_, _, err = unicode.ReadUtf8Char(buf)
+		if !errors.As(err, &invalidSequenceErr) {
+			t.Errorf("unicode.ReadUtf8Char read invalid sequences: %v", origSeqs)
+		}
+	}
+
+	// broken sequence does not swallow the following character
+	origSeqs = []byte{
+		0xE3, 0x81, 0x61,
+	}
+	buf = bufio.NewReader(bytes.NewBuffer(origSeqs))
+	_, _, err = unicode.ReadUtf8Char(buf)
+	if !errors.As(err, &invalidSequenceErr) {
+		t.Errorf("unicode.ReadUtf8Char read invalid sequences: %v", origSeqs)
+	} else if !reflect.DeepEqual(invalidSequenceErr.Sequences(), []byte{0xE3, 0x81}) {
+		t.Errorf("unicode.ReadUtf8Char returns invalid sequences %v, but expected value is %v", invalidSequenceErr.Sequences(), []byte{0xE3, 0x81})
+	}
+	if r, _, err := unicode.ReadUtf8Char(buf); err != nil || r != 'a' {
+		t.Errorf("unicode.ReadUtf8Char returns %s (error: %v) after invalid sequences, but expected character is 'a'", strconv.QuoteRuneToGraphic(r), err)
+	}
+
 	// lack needed byte
 	origSeqs = []byte{
 		0xF0, 0x9F, 0x9B,
@@ -78,6 +102,8 @@ func TestReadUtf8Char(t *testing.T) {
 	var unexpectedEofErr *unicode.UnexpectedEofErr
 	if !errors.As(err, &unexpectedEofErr) {
 		t.Errorf("unicode.ReadUtf8Char read invalid sequences: %v", origSeqs)
+	} else if !reflect.DeepEqual(unexpectedEofErr.Sequences(), origSeqs) {
+		t.Errorf("unicode.ReadUtf8Char returns read sequences %v, but expected value is %v", unexpectedEofErr.Sequences(), origSeqs)
 	}
 
 	// I/O error occured
@@ -87,3 +113,37 @@ func TestReadUtf8Char(t *testing.T) {
 		t.Error("unicode.ReadUtf8Char ignore I/O error")
 	}
 }
+
+func TestEncodeUtf8Char(t *testing.T) {
+	chars := []Char{
+		{
+			char:       'a',
+			byteStream: []byte{0x61},
+		},
+		{
+			char:       'あ',
+			byteStream: []byte{0xE3, 0x81, 0x82},
+		},
+		{
+			char:       '🐧',
+			byteStream: []byte{0xF0, 0x9F, 0x90, 0xA7},
+		},
+	}
+	for _, c := range chars {
+		bs, err := unicode.EncodeUtf8Char(c.char)
+		if err != nil {
+			t.Errorf("unicode.EncodeUtf8Char returns error: %v", err)
+		}
+		if !reflect.DeepEqual(bs, c.byteStream) {
+			t.Errorf("unicode.EncodeUtf8Char returns %v, but expected value is %v", bs, c.byteStream)
+		}
+	}
+
+	for _, r := range []rune{0xD800, 0x110000, -1} {
+		_, err := unicode.EncodeUtf8Char(r)
+		var invalidCodePointErr *unicode.InvalidCodePointErr
+		if !errors.As(err, &invalidCodePointErr) {
+			t.Errorf("unicode.EncodeUtf8Char encode invalid code point: %U", r)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moba1/usd/encoder"
+	"github.com/moba1/usd/unicode"
+	"github.com/moba1/usd/unique"
+)
+
+var (
+	uniqueBy    string
+	uniqueOrder = unique.ByFirst
+)
+
+// uniqueFlags adds the -unique and -sort options to a dump subcommand.
+func uniqueFlags(fs *flag.FlagSet) {
+	fs.Func("unique", "print each distinct `unit` once with its count (value: CodePoint|Grapheme)", func(s string) error {
+		switch s {
+		case "CodePoint", "Grapheme":
+			uniqueBy = s
+		default:
+			return fmt.Errorf("invalid unit: %s", s)
+		}
+		return nil
+	})
+	fs.Func("sort", "`order` of the distinct characters. default is 'First' (value: First|Count|CodePoint)", func(s string) error {
+		switch s {
+		case "First":
+			uniqueOrder = unique.ByFirst
+		case "Count":
+			uniqueOrder = unique.ByCount
+		case "CodePoint":
+			uniqueOrder = unique.ByCodePoint
+		default:
+			return fmt.Errorf("invalid order: %s", s)
+		}
+		return nil
+	})
+}
+
+// uniqueRecord returns the record of e with its count and first offset as the
+// extra columns.
+func uniqueRecord(e unique.Entry) encoder.CharRecord {
+	record := encoder.CharRecord{
+		Bytes:    e.Bytes,
+		Offset:   e.FirstOffset,
+		Encoding: readerEncoding,
+		Extra:    []interface{}{e.Count, e.FirstOffset},
+	}
+	switch {
+	case e.Invalid:
+		record.Rune = e.Runes[0]
+		record.Err = unicode.NewInvalidSequenceErr(e.Bytes)
+	case len(e.Runes) == 1:
+		record.Rune = e.Runes[0]
+	default:
+		record.Runes = e.Runes
+	}
+	return record
+}
+
+func dumpUnique() error {
+	counter := unique.NewCounter(uniqueBy == "Grapheme")
+	scanner := unicode.NewScanner(os.Stdin, reader)
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("can't read input (reason; %s)", err.Error())
+		}
+		counter.Add(c)
+	}
+	counter.Finish()
+
+	table := newTableEncoder(tableOutput)
+	if !noHeader {
+		table.SetHeader(append(append([]string{}, dumpHeader...), "Count", "First Offset"))
+	}
+	for _, e := range counter.Entries(uniqueOrder) {
+		table.AppendRecord(uniqueRecord(e))
+	}
+	return table.Render()
+}
@@ -0,0 +1,133 @@
+// Package unique counts the distinct characters or grapheme clusters of an
+// input. Memory grows with the number of distinct ones, not with the input.
+package unique
+
+import (
+	"sort"
+
+	"github.com/moba1/usd/grapheme"
+	"github.com/moba1/usd/unicode"
+)
+
+// Order is the order of the entries returned by Counter.Entries.
+type Order int
+
+const (
+	// ByFirst orders entries by their first occurrences.
+	ByFirst Order = iota
+	// ByCount orders entries by descending count.
+	ByCount
+	// ByCodePoint orders entries by their code points.
+	ByCodePoint
+)
+
+// Entry is a distinct character or grapheme cluster.
+type Entry struct {
+	// Runes are the characters of the entry. An invalid sequence is a single
+	// U+FFFD.
+	Runes []rune
+	// Bytes are the encoded bytes of the first occurrence.
+	Bytes       []byte
+	Invalid     bool
+	Count       int64
+	FirstOffset int64
+}
+
+type Counter struct {
+	byGrapheme bool
+	entries    map[string]*Entry
+	splitter   grapheme.Splitter
+	// cluster is the grapheme cluster which is being read.
+	cluster *Entry
+}
+
+// NewCounter returns a Counter of characters, or of grapheme clusters when
+// byGrapheme is true.
+func NewCounter(byGrapheme bool) *Counter {
+	return &Counter{
+		byGrapheme: byGrapheme,
+		entries:    map[string]*Entry{},
+	}
+}
+
+// key distinguishes invalid sequences from U+FFFD and from each other.
+func key(e *Entry) string {
+	if e.Invalid {
+		return "\xFF" + string(e.Bytes)
+	}
+	return string(e.Runes)
+}
+
+func (c *Counter) count(e *Entry) {
+	k := key(e)
+	if entry, ok := c.entries[k]; ok {
+		entry.Count++
+		return
+	}
+	e.Count = 1
+	c.entries[k] = e
+}
+
+// Add counts the next character of the input.
+func (c *Counter) Add(ch unicode.Char) {
+	if !c.byGrapheme || ch.Err != nil {
+		c.Finish()
+		c.splitter = grapheme.Splitter{}
+		c.count(&Entry{
+			Runes:       []rune{ch.Rune},
+			Bytes:       append([]byte{}, ch.Bytes...),
+			Invalid:     ch.Err != nil,
+			FirstOffset: ch.Offset,
+		})
+		return
+	}
+	if c.splitter.IsBoundary(ch.Rune) {
+		c.Finish()
+		c.cluster = &Entry{FirstOffset: ch.Offset}
+	}
+	c.cluster.Runes = append(c.cluster.Runes, ch.Rune)
+	c.cluster.Bytes = append(c.cluster.Bytes, ch.Bytes...)
+}
+
+// Finish counts the last grapheme cluster. It must be called after the last
+// Add.
+func (c *Counter) Finish() {
+	if c.cluster != nil {
+		c.count(c.cluster)
+		c.cluster = nil
+	}
+}
+
+func lessRunes(a, b []rune) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// Entries returns the distinct characters or grapheme clusters in order.
+// Ties are broken by the first occurrence.
+func (c *Counter) Entries(order Order) []Entry {
+	entries := make([]Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case order == ByCount && a.Count != b.Count:
+			return a.Count > b.Count
+		case order == ByCodePoint && a.Invalid != b.Invalid:
+			return !a.Invalid
+		case order == ByCodePoint && key(&a) != key(&b):
+			if a.Invalid {
+				return string(a.Bytes) < string(b.Bytes)
+			}
+			return lessRunes(a.Runes, b.Runes)
+		}
+		return a.FirstOffset < b.FirstOffset
+	})
+	return entries
+}
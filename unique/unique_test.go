@@ -0,0 +1,63 @@
+package unique_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/moba1/usd/unicode"
+	"github.com/moba1/usd/unique"
+)
+
+func count(t *testing.T, input []byte, byGrapheme bool) *unique.Counter {
+	counter := unique.NewCounter(byGrapheme)
+	scanner := unicode.NewScanner(bytes.NewBuffer(input), unicode.ReadUtf8Char)
+	for {
+		c, err := scanner.Scan()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Scanner.Scan returns error: %v", err)
+		}
+		counter.Add(c)
+	}
+	counter.Finish()
+	return counter
+}
+
+type entry struct {
+	text        string
+	count       int64
+	firstOffset int64
+}
+
+func check(t *testing.T, entries []unique.Entry, expected []entry) {
+	if len(entries) != len(expected) {
+		t.Fatalf("Counter.Entries returns %d entries, but expected value is %d", len(entries), len(expected))
+	}
+	for i, e := range entries {
+		if string(e.Runes) != expected[i].text || e.Count != expected[i].count || e.FirstOffset != expected[i].firstOffset {
+			t.Errorf("Counter.Entries()[%d] is %q (count %d, first offset %d), but expected value is %+v", i, string(e.Runes), e.Count, e.FirstOffset, expected[i])
+		}
+	}
+}
+
+func TestCounter_Entries(t *testing.T) {
+	counter := count(t, []byte("bab\u00E9c\xFEb\xFE\xFD"), false)
+	check(t, counter.Entries(unique.ByFirst), []entry{
+		{"b", 3, 0}, {"a", 1, 1}, {"\u00E9", 1, 3}, {"c", 1, 5}, {"\uFFFD", 2, 6}, {"\uFFFD", 1, 9},
+	})
+	check(t, counter.Entries(unique.ByCount), []entry{
+		{"b", 3, 0}, {"\uFFFD", 2, 6}, {"a", 1, 1}, {"\u00E9", 1, 3}, {"c", 1, 5}, {"\uFFFD", 1, 9},
+	})
+	check(t, counter.Entries(unique.ByCodePoint), []entry{
+		{"a", 1, 1}, {"b", 3, 0}, {"c", 1, 5}, {"\u00E9", 1, 3}, {"\uFFFD", 1, 9}, {"\uFFFD", 2, 6},
+	})
+}
+
+func TestCounter_Grapheme(t *testing.T) {
+	counter := count(t, []byte("e\u0301e\r\ne\u0301\U0001F1EF\U0001F1F5"), true)
+	check(t, counter.Entries(unique.ByFirst), []entry{
+		{"e\u0301", 2, 0}, {"e", 1, 3}, {"\r\n", 1, 4}, {"\U0001F1EF\U0001F1F5", 1, 9},
+	})
+}
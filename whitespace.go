@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/moba1/usd/ucd"
+	"github.com/moba1/usd/unicode"
+	"github.com/moba1/usd/whitespace"
+)
+
+const whitespaceCmdName = "whitespace"
+
+func parseWhitespaceCmd(args []string) func() error {
+	whitespaceCmd := flag.NewFlagSet(whitespaceCmdName, flag.ExitOnError)
+	lookupCharset := charsetFlags(whitespaceCmd)
+	var count bool
+	whitespaceCmd.BoolVar(&count, "count", false, "count each character instead of showing its positions")
+	whitespaceCmd.Usage = func() {
+		stmts := []string{
+			fmt.Sprintf("Usage of %s:", whitespaceCmdName),
+			fmt.Sprintf("  %s [option] [file]...", whitespaceCmdName),
+			"Standard input is read when no file is given.",
+			"Options:",
+			"  -help",
+			"        show help",
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintln(whitespaceCmd.Output(), stmt)
+		}
+		whitespaceCmd.PrintDefaults()
+	}
+	if err := whitespaceCmd.Parse(args); err != nil {
+		log.Fatalln(err)
+	}
+
+	return func() error {
+		cs, err := lookupCharset()
+		if err != nil {
+			return err
+		}
+		table := newTableEncoder(tableOutput)
+		if !noHeader {
+			if count {
+				table.SetHeader([]string{"Code Point", "Name", "Kind", "Count", "Replacement"})
+			} else {
+				table.SetHeader([]string{"File", "Line", "Column", "Offset", "Code Point", "Name", "Kind", "Replacement"})
+			}
+		}
+		counts := map[rune]int{}
+		err = readFiles(whitespaceCmd.Args(), func(fileName string, r io.Reader) error {
+			scanner := unicode.NewScanner(r, cs.Read)
+			for {
+				c, err := scanner.Scan()
+				if err == io.EOF {
+					return nil
+				} else if err != nil {
+					return fmt.Errorf("can't read %s (reason; %s)", fileName, err.Error())
+				}
+				if c.Err != nil {
+					return fmt.Errorf("can't read %s at line %d, column %d (reason; %s)", fileName, c.Line, c.Column, c.Err.Error())
+				}
+				kind, ok := whitespace.Classify(c.Rune)
+				if !ok {
+					continue
+				}
+				counts[c.Rune]++
+				if count {
+					continue
+				}
+				table.Append([]string{
+					fileName,
+					strconv.Itoa(c.Line),
+					strconv.Itoa(c.Column),
+					strconv.FormatInt(c.Offset, 10),
+					fmt.Sprintf("%U", c.Rune),
+					characterName(c.Rune),
+					kind.String(),
+					strconv.Quote(whitespace.Replacement(kind)),
+				})
+			}
+		})
+		if count {
+			chars := make([]rune, 0, len(counts))
+			for c := range counts {
+				chars = append(chars, c)
+			}
+			sort.Slice(chars, func(i, j int) bool {
+				return chars[i] < chars[j]
+			})
+			for _, c := range chars {
+				kind, _ := whitespace.Classify(c)
+				table.Append([]string{
+					fmt.Sprintf("%U", c),
+					characterName(c),
+					kind.String(),
+					strconv.Itoa(counts[c]),
+					strconv.Quote(whitespace.Replacement(kind)),
+				})
+			}
+		}
+		if renderErr := table.Render(); err == nil {
+			err = renderErr
+		}
+		return err
+	}
+}
+
+// characterName is ucd.Name, or the first alias of the characters without a
+// name such as U+0085 NEXT LINE.
+func characterName(c rune) string {
+	name := ucd.Name(c)
+	if aliases := ucd.Aliases(c); name == "" && len(aliases) > 0 {
+		name = aliases[0].Name
+	}
+	return name
+}
@@ -0,0 +1,68 @@
+// Package whitespace classifies the invisible characters which look like
+// whitespace or nothing at all, and suggests ASCII replacements for them.
+package whitespace
+
+import (
+	"unicode"
+
+	"github.com/moba1/usd/ucd"
+)
+
+// Kind is a kind of invisible character.
+type Kind int
+
+const (
+	// Space is a non-ASCII White_Space character such as NO-BREAK SPACE.
+	Space Kind = iota
+	// LineBreak is a non-ASCII White_Space character which breaks lines.
+	LineBreak
+	// Format is a format character (General_Category Cf).
+	Format
+	// Ignorable is a Default_Ignorable_Code_Point which isn't a format
+	// character, such as a variation selector or a Hangul filler.
+	Ignorable
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Space:
+		return "Space"
+	case LineBreak:
+		return "LineBreak"
+	case Format:
+		return "Format"
+	case Ignorable:
+		return "Ignorable"
+	}
+	return "Unknown"
+}
+
+// Classify returns the kind of c, or false when c is ASCII or visible.
+func Classify(c rune) (Kind, bool) {
+	switch {
+	case c < 0x80:
+		return 0, false
+	case c == 0x0085 || c == 0x2028 || c == 0x2029:
+		return LineBreak, true
+	case unicode.Is(unicode.White_Space, c):
+		return Space, true
+	case unicode.Is(unicode.Cf, c):
+		return Format, true
+	case ucd.IsDefaultIgnorable(c):
+		return Ignorable, true
+	}
+	return 0, false
+}
+
+// Replacement returns the suggested ASCII replacement of a character
+// classified by Classify: a space for spaces, a line feed for line breaks and
+// nothing for the others.
+func Replacement(k Kind) string {
+	switch k {
+	case Space:
+		return " "
+	case LineBreak:
+		return "\n"
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package whitespace_test
+
+import (
+	"testing"
+
+	"github.com/moba1/usd/whitespace"
+)
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		char        rune
+		kind        whitespace.Kind
+		replacement string
+	}{
+		{0x00A0, whitespace.Space, " "},
+		{0x3000, whitespace.Space, " "},
+		{0x2028, whitespace.LineBreak, "\n"},
+		{0x00AD, whitespace.Format, ""},
+		{0x200D, whitespace.Format, ""},
+		{0x2060, whitespace.Format, ""},
+		{0x3164, whitespace.Ignorable, ""},
+		{0xFE0F, whitespace.Ignorable, ""},
+	}
+	for _, tc := range testCases {
+		kind, ok := whitespace.Classify(tc.char)
+		if !ok || kind != tc.kind {
+			t.Errorf("whitespace.Classify(%U) returns (%v, %v), but expected value is %v", tc.char, kind, ok, tc.kind)
+		}
+		if replacement := whitespace.Replacement(kind); replacement != tc.replacement {
+			t.Errorf("whitespace.Replacement(%v) returns %q, but expected value is %q", kind, replacement, tc.replacement)
+		}
+	}
+
+	for _, c := range []rune{' ', '\t', 'a', 'あ', 0x0301} {
+		if kind, ok := whitespace.Classify(c); ok {
+			t.Errorf("whitespace.Classify(%U) returns %v, but expected value is false", c, kind)
+		}
+	}
+}